@@ -0,0 +1,201 @@
+package ergani
+
+import (
+	"fmt"
+	"time"
+)
+
+// DiffDailySchedule computes the minimal amendment that turns the previously
+// submitted schedule into the desired one. Only employee days that were added or
+// changed are kept; days that were removed are declared as Absent. The header
+// (branch, date range and comments) is taken from the desired schedule.
+//
+// A removed day is sent as a single Absent row with zero start and end times,
+// i.e. {"f_type": "ΜΕ", "f_from": "00:00", "f_to": "00:00"}, the same form the
+// SDK uses for every row without working hours.
+func DiffDailySchedule(previous, desired CompanyDailySchedule) CompanyDailySchedule {
+	amendment := desired
+	amendment.EmployeeSchedules = nil
+
+	old := make(map[string]EmployeeDailySchedule, len(previous.EmployeeSchedules))
+	for _, es := range previous.EmployeeSchedules {
+		old[dailyScheduleKey(es)] = es
+	}
+
+	seen := make(map[string]bool, len(desired.EmployeeSchedules))
+	for _, es := range desired.EmployeeSchedules {
+		key := dailyScheduleKey(es)
+		seen[key] = true
+		if prev, ok := old[key]; ok && sameDailySchedule(prev, es) {
+			continue
+		}
+		amendment.EmployeeSchedules = append(amendment.EmployeeSchedules, es)
+	}
+
+	for _, es := range previous.EmployeeSchedules {
+		if seen[dailyScheduleKey(es)] {
+			continue
+		}
+		es.WorkdayDetails = []WorkdayDetails{{WorkType: Absent}}
+		amendment.EmployeeSchedules = append(amendment.EmployeeSchedules, es)
+	}
+
+	return amendment
+}
+
+// DiffWeeklySchedule computes the minimal amendment that turns the previously
+// submitted weekly schedule into the desired one. Only employee weekdays that were
+// added or changed are kept; weekdays that were removed are declared as Absent. The
+// header (branch, date range and comments) is taken from the desired schedule.
+// Removed weekdays are sent in the same form as removed days in DiffDailySchedule.
+func DiffWeeklySchedule(previous, desired CompanyWeeklySchedule) CompanyWeeklySchedule {
+	amendment := desired
+	amendment.EmployeeSchedules = nil
+
+	old := make(map[string]EmployeeWeeklySchedule, len(previous.EmployeeSchedules))
+	for _, es := range previous.EmployeeSchedules {
+		old[weeklyScheduleKey(es)] = es
+	}
+
+	seen := make(map[string]bool, len(desired.EmployeeSchedules))
+	for _, es := range desired.EmployeeSchedules {
+		key := weeklyScheduleKey(es)
+		seen[key] = true
+		if prev, ok := old[key]; ok && sameWeeklySchedule(prev, es) {
+			continue
+		}
+		amendment.EmployeeSchedules = append(amendment.EmployeeSchedules, es)
+	}
+
+	for _, es := range previous.EmployeeSchedules {
+		if seen[weeklyScheduleKey(es)] {
+			continue
+		}
+		es.WorkdayDetails = []WorkdayDetails{{WorkType: Absent}}
+		amendment.EmployeeSchedules = append(amendment.EmployeeSchedules, es)
+	}
+
+	return amendment
+}
+
+// NewDailyScheduleAmendment diffs the schedules, links the result to the original
+// submission and checks that no amended day has already started at the given time.
+func NewDailyScheduleAmendment(original SubmissionResponse, previous, desired CompanyDailySchedule, now time.Time) (CompanyDailySchedule, error) {
+	amendment := DiffDailySchedule(previous, desired)
+	if len(amendment.EmployeeSchedules) == 0 {
+		return CompanyDailySchedule{}, ErrNoScheduleChanges
+	}
+
+	old := make(map[string]EmployeeDailySchedule, len(previous.EmployeeSchedules))
+	for _, es := range previous.EmployeeSchedules {
+		old[dailyScheduleKey(es)] = es
+	}
+	for _, es := range amendment.EmployeeSchedules {
+		details := append(append([]WorkdayDetails(nil), es.WorkdayDetails...), old[dailyScheduleKey(es)].WorkdayDetails...)
+		if err := checkNotStarted(es.EmployeeTaxID, es.ScheduleDate.Time, details, now); err != nil {
+			return CompanyDailySchedule{}, err
+		}
+	}
+
+	amendment.RelatedProtocolID = original.Protocol
+	amendment.RelatedProtocolDate = &Date{Time: original.SubmissionDate}
+	return amendment, nil
+}
+
+// NewWeeklyScheduleAmendment diffs the schedules, links the result to the original
+// submission and checks that no occurrence of an amended weekday within the
+// desired date range has already started at the given time. To amend a schedule
+// that is already in effect, move the desired StartDate forward.
+func NewWeeklyScheduleAmendment(original SubmissionResponse, previous, desired CompanyWeeklySchedule, now time.Time) (CompanyWeeklySchedule, error) {
+	amendment := DiffWeeklySchedule(previous, desired)
+	if len(amendment.EmployeeSchedules) == 0 {
+		return CompanyWeeklySchedule{}, ErrNoScheduleChanges
+	}
+
+	old := make(map[string]EmployeeWeeklySchedule, len(previous.EmployeeSchedules))
+	for _, es := range previous.EmployeeSchedules {
+		old[weeklyScheduleKey(es)] = es
+	}
+	for _, es := range amendment.EmployeeSchedules {
+		details := append(append([]WorkdayDetails(nil), es.WorkdayDetails...), old[weeklyScheduleKey(es)].WorkdayDetails...)
+		for d := desired.StartDate.Time; !d.After(desired.EndDate.Time); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() != es.ScheduleDay.Weekday {
+				continue
+			}
+			if err := checkNotStarted(es.EmployeeTaxID, d, details, now); err != nil {
+				return CompanyWeeklySchedule{}, err
+			}
+		}
+	}
+
+	amendment.RelatedProtocolID = original.Protocol
+	amendment.RelatedProtocolDate = &Date{Time: original.SubmissionDate}
+	return amendment, nil
+}
+
+// checkNotStarted returns a *ValidationError when the given day lies in the past or
+// when any of its working periods has already started at the given time.
+func checkNotStarted(employeeTaxID string, day time.Time, details []WorkdayDetails, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location())
+
+	if date.Before(today) {
+		return &ValidationError{
+			Field:   "ScheduleDate",
+			Message: fmt.Sprintf("schedule of %s on %s is in the past", employeeTaxID, date.Format("02/01/2006")),
+		}
+	}
+	if date.After(today) {
+		return nil
+	}
+
+	for _, wd := range details {
+		if !wd.WorkType.IsWork() {
+			continue
+		}
+		start := date.Add(time.Duration(wd.StartTime.Hour())*time.Hour + time.Duration(wd.StartTime.Minute())*time.Minute)
+		if !start.After(now) {
+			return &ValidationError{
+				Field:   "WorkdayDetails",
+				Message: fmt.Sprintf("schedule of %s on %s has already started at %s", employeeTaxID, date.Format("02/01/2006"), wd.StartTime.Format("15:04")),
+			}
+		}
+	}
+	return nil
+}
+
+func dailyScheduleKey(es EmployeeDailySchedule) string {
+	return es.EmployeeTaxID + "|" + es.ScheduleDate.Format("2006-01-02")
+}
+
+func weeklyScheduleKey(es EmployeeWeeklySchedule) string {
+	return fmt.Sprintf("%s|%d", es.EmployeeTaxID, es.ScheduleDay.Weekday)
+}
+
+func sameDailySchedule(a, b EmployeeDailySchedule) bool {
+	return a.EmployeeLastName == b.EmployeeLastName &&
+		a.EmployeeFirstName == b.EmployeeFirstName &&
+		sameWorkdayDetails(a.WorkdayDetails, b.WorkdayDetails)
+}
+
+func sameWeeklySchedule(a, b EmployeeWeeklySchedule) bool {
+	return a.EmployeeLastName == b.EmployeeLastName &&
+		a.EmployeeFirstName == b.EmployeeFirstName &&
+		sameWorkdayDetails(a.WorkdayDetails, b.WorkdayDetails)
+}
+
+// sameWorkdayDetails compares working periods the way they are sent to the API,
+// i.e. by work type and HH:MM times.
+func sameWorkdayDetails(a, b []WorkdayDetails) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].WorkType != b[i].WorkType ||
+			a[i].StartTime.Format("15:04") != b[i].StartTime.Format("15:04") ||
+			a[i].EndTime.Format("15:04") != b[i].EndTime.Format("15:04") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package ergani
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDiffDailySchedule(t *testing.T) {
+	at := func(h int) Time { return Time{Time: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)} }
+	day := Date{Time: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC)}
+
+	previous := CompanyDailySchedule{
+		BusinessBranchNumber: 1,
+		EmployeeSchedules: []EmployeeDailySchedule{
+			{EmployeeTaxID: "111111111", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(9), EndTime: at(17)}}},
+			{EmployeeTaxID: "222222222", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(9), EndTime: at(17)}}},
+			{EmployeeTaxID: "333333333", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(9), EndTime: at(17)}}},
+		},
+	}
+	desired := CompanyDailySchedule{
+		BusinessBranchNumber: 1,
+		EmployeeSchedules: []EmployeeDailySchedule{
+			{EmployeeTaxID: "111111111", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(9), EndTime: at(17)}}},
+			{EmployeeTaxID: "222222222", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(12), EndTime: at(20)}}},
+			{EmployeeTaxID: "444444444", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromHome, StartTime: at(9), EndTime: at(13)}}},
+		},
+	}
+
+	amendment := DiffDailySchedule(previous, desired)
+
+	got := make(map[string]ScheduleWorkType)
+	for _, es := range amendment.EmployeeSchedules {
+		got[es.EmployeeTaxID] = es.WorkdayDetails[0].WorkType
+	}
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 amended employees, got %d", len(got))
+	}
+	if _, ok := got["111111111"]; ok {
+		t.Error("Expected the unchanged schedule to be left out")
+	}
+	if got["222222222"] != WorkFromOffice {
+		t.Error("Expected the changed schedule to be included")
+	}
+	if got["333333333"] != Absent {
+		t.Errorf("Expected the removed schedule to be declared absent, got %s", got["333333333"])
+	}
+	if got["444444444"] != WorkFromHome {
+		t.Error("Expected the added schedule to be included")
+	}
+}
+
+func TestDiffDailySchedule_RemovedDayJSON(t *testing.T) {
+	at := func(h int) Time { return Time{Time: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)} }
+	day := Date{Time: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC)}
+
+	previous := CompanyDailySchedule{
+		BusinessBranchNumber: 1,
+		EmployeeSchedules: []EmployeeDailySchedule{
+			{EmployeeTaxID: "111111111", ScheduleDate: day, WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(9), EndTime: at(17)}}},
+		},
+	}
+
+	amendment := DiffDailySchedule(previous, CompanyDailySchedule{BusinessBranchNumber: 1})
+	if len(amendment.EmployeeSchedules) != 1 {
+		t.Fatalf("Expected 1 amended employee, got %d", len(amendment.EmployeeSchedules))
+	}
+
+	data, err := json.Marshal(amendment.EmployeeSchedules[0].WorkdayDetails)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got []map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]string{"f_type": "ΜΕ", "f_from": "00:00", "f_to": "00:00"}
+	if len(got) != 1 || len(got[0]) != len(want) {
+		t.Fatalf("Expected a single row with %v, got %s", want, data)
+	}
+	for k, v := range want {
+		if got[0][k] != v {
+			t.Errorf("Expected %s to be '%s', got '%s'", k, v, got[0][k])
+		}
+	}
+}
+
+func TestNewDailyScheduleAmendment(t *testing.T) {
+	at := func(h int) Time { return Time{Time: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)} }
+	original := SubmissionResponse{Protocol: "proto123", SubmissionDate: time.Date(2025, 7, 12, 11, 0, 0, 0, time.UTC)}
+	now := time.Date(2025, 7, 14, 10, 0, 0, 0, time.UTC)
+
+	schedule := func(day, from, to int) CompanyDailySchedule {
+		return CompanyDailySchedule{
+			BusinessBranchNumber: 1,
+			EmployeeSchedules: []EmployeeDailySchedule{{
+				EmployeeTaxID:  "111111111",
+				ScheduleDate:   Date{Time: time.Date(2025, 7, day, 0, 0, 0, 0, time.UTC)},
+				WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(from), EndTime: at(to)}},
+			}},
+		}
+	}
+
+	t.Run("LaterToday", func(t *testing.T) {
+		amendment, err := NewDailyScheduleAmendment(original, schedule(14, 12, 20), schedule(14, 13, 21), now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if amendment.RelatedProtocolID != "proto123" {
+			t.Errorf("Expected amendment to reference proto123, got '%s'", amendment.RelatedProtocolID)
+		}
+	})
+
+	t.Run("AlreadyStarted", func(t *testing.T) {
+		_, err := NewDailyScheduleAmendment(original, schedule(14, 9, 17), schedule(14, 13, 21), now)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+		}
+	})
+
+	t.Run("PastDay", func(t *testing.T) {
+		_, err := NewDailyScheduleAmendment(original, schedule(13, 12, 20), schedule(13, 13, 21), now)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+		}
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		_, err := NewDailyScheduleAmendment(original, schedule(15, 9, 17), schedule(15, 9, 17), now)
+		if !errors.Is(err, ErrNoScheduleChanges) {
+			t.Fatalf("Expected ErrNoScheduleChanges, got %v", err)
+		}
+	})
+}
+
+func TestNewWeeklyScheduleAmendment(t *testing.T) {
+	at := func(h int) Time { return Time{Time: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)} }
+	original := SubmissionResponse{Protocol: "proto222", SubmissionDate: time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)}
+	// Wednesday.
+	now := time.Date(2025, 7, 16, 10, 0, 0, 0, time.UTC)
+
+	schedule := func(start int, day time.Weekday, from int) CompanyWeeklySchedule {
+		return CompanyWeeklySchedule{
+			BusinessBranchNumber: 1,
+			StartDate:            Date{Time: time.Date(2025, 7, start, 0, 0, 0, 0, time.UTC)},
+			EndDate:              Date{Time: time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC)},
+			EmployeeSchedules: []EmployeeWeeklySchedule{{
+				EmployeeTaxID:  "111111111",
+				ScheduleDay:    Weekday{Weekday: day},
+				WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(from), EndTime: at(from + 8)}},
+			}},
+		}
+	}
+
+	if _, err := NewWeeklyScheduleAmendment(original, schedule(14, time.Monday, 9), schedule(14, time.Monday, 10), now); err == nil {
+		t.Error("Expected an error when amending a weekday that already passed within the range")
+	}
+
+	amendment, err := NewWeeklyScheduleAmendment(original, schedule(14, time.Monday, 9), schedule(17, time.Monday, 10), now)
+	if err != nil {
+		t.Fatalf("Unexpected error after moving the start date forward: %v", err)
+	}
+	if len(amendment.EmployeeSchedules) != 1 || amendment.RelatedProtocolID != "proto222" {
+		t.Errorf("Expected a single amended weekday referencing proto222, got %+v", amendment)
+	}
+
+	if _, err := NewWeeklyScheduleAmendment(original, schedule(14, time.Thursday, 9), schedule(14, time.Thursday, 10), now); err != nil {
+		t.Errorf("Unexpected error amending a future weekday: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package ergani
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchItem is the outcome of submitting one company (branch) document of a batch.
+type BatchItem struct {
+	// Index is the position of the document in the submitted slice.
+	Index                int
+	BusinessBranchNumber int
+	Responses            []SubmissionResponse
+	// Err is the error returned for the document, e.g. an *APIError.
+	Err error
+}
+
+// Succeeded reports whether the document was accepted.
+func (i BatchItem) Succeeded() bool {
+	return i.Err == nil
+}
+
+// BatchResult reports the outcome of every company document of a batch submission,
+// in input order.
+type BatchResult struct {
+	Items []BatchItem
+}
+
+// Succeeded returns the items that were accepted.
+func (r *BatchResult) Succeeded() []BatchItem {
+	var items []BatchItem
+	for _, item := range r.Items {
+		if item.Succeeded() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Failed returns the items that were rejected or could not be sent.
+func (r *BatchResult) Failed() []BatchItem {
+	var items []BatchItem
+	for _, item := range r.Items {
+		if !item.Succeeded() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Err returns a *BatchError if any item failed and nil otherwise.
+func (r *BatchResult) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	return &BatchError{Failed: len(failed), Total: len(r.Items), First: failed[0].Err}
+}
+
+// SubmitWorkCardBatch submits every CompanyWorkCard in its own request, so that a
+// rejected branch does not block the others, and reports the outcome per branch.
+// Up to MaxConcurrentRequests documents are sent in parallel.
+func (c *Client) SubmitWorkCardBatch(ctx context.Context, companyWorkCards []CompanyWorkCard) *BatchResult {
+	return submitBatch(ctx, c.maxConcurrentRequests, companyWorkCards,
+		func(cwc CompanyWorkCard) int { return cwc.BusinessBranchNumber },
+		c.SubmitWorkCard)
+}
+
+// SubmitOvertimeBatch submits every CompanyOvertime in its own request and reports
+// the outcome per branch. See SubmitWorkCardBatch.
+func (c *Client) SubmitOvertimeBatch(ctx context.Context, companyOvertimes []CompanyOvertime) *BatchResult {
+	return submitBatch(ctx, c.maxConcurrentRequests, companyOvertimes,
+		func(co CompanyOvertime) int { return co.BusinessBranchNumber },
+		c.SubmitOvertime)
+}
+
+// SubmitDailyScheduleBatch submits every CompanyDailySchedule in its own request and
+// reports the outcome per branch. See SubmitWorkCardBatch.
+func (c *Client) SubmitDailyScheduleBatch(ctx context.Context, companyDailySchedules []CompanyDailySchedule) *BatchResult {
+	return submitBatch(ctx, c.maxConcurrentRequests, companyDailySchedules,
+		func(cds CompanyDailySchedule) int { return cds.BusinessBranchNumber },
+		c.SubmitDailySchedule)
+}
+
+// SubmitWeeklyScheduleBatch submits every CompanyWeeklySchedule in its own request
+// and reports the outcome per branch. See SubmitWorkCardBatch.
+func (c *Client) SubmitWeeklyScheduleBatch(ctx context.Context, companyWeeklySchedules []CompanyWeeklySchedule) *BatchResult {
+	return submitBatch(ctx, c.maxConcurrentRequests, companyWeeklySchedules,
+		func(cws CompanyWeeklySchedule) int { return cws.BusinessBranchNumber },
+		c.SubmitWeeklySchedule)
+}
+
+// submitBatch submits each document independently with bounded parallelism.
+func submitBatch[T any](ctx context.Context, concurrency int, docs []T, branch func(T) int,
+	submit func(context.Context, []T) ([]SubmissionResponse, error)) *BatchResult {
+	result := &BatchResult{Items: make([]BatchItem, len(docs))}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, doc := range docs {
+		result.Items[i] = BatchItem{Index: i, BusinessBranchNumber: branch(doc)}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *BatchItem, doc T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item.Responses, item.Err = submit(ctx, []T{doc})
+		}(&result.Items[i], doc)
+	}
+	wg.Wait()
+
+	return result
+}
@@ -0,0 +1,48 @@
+package ergani
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSubmitWorkCardBatch(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{
+		Username:              "testuser",
+		Password:              "testpass",
+		BaseURL:               server.URL,
+		MaxConcurrentRequests: 2,
+	})
+
+	companies := testCompanyWorkCards(3, 2)
+	companies[1].Comments = "FORCE_ERROR"
+
+	result := client.SubmitWorkCardBatch(context.Background(), companies)
+
+	if len(result.Items) != 3 {
+		t.Fatalf("Expected 3 batch items, got %d", len(result.Items))
+	}
+	if len(result.Succeeded()) != 2 {
+		t.Errorf("Expected 2 succeeded items, got %d", len(result.Succeeded()))
+	}
+
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].BusinessBranchNumber != 1 {
+		t.Fatalf("Expected branch 1 to fail, got %+v", failed)
+	}
+	if result.Items[2].Responses[0].Protocol != "proto456" {
+		t.Errorf("Expected the branch after the failure to be submitted, got %+v", result.Items[2])
+	}
+
+	var batchErr *BatchError
+	if !errors.As(result.Err(), &batchErr) || batchErr.Failed != 1 || batchErr.Total != 3 {
+		t.Fatalf("Expected a BatchError for 1 of 3 items, got %v", result.Err())
+	}
+	var apiErr *APIError
+	if !errors.As(result.Err(), &apiErr) {
+		t.Errorf("Expected the batch error to unwrap to an APIError, got %v", result.Err())
+	}
+}
@@ -0,0 +1,166 @@
+package ergani
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// WorkCardChunk is the part of a work card submission that was sent in a single
+// request. The protocols in Responses cover exactly the records in CompanyWorkCards.
+type WorkCardChunk struct {
+	Index            int
+	CompanyWorkCards []CompanyWorkCard
+	Responses        []SubmissionResponse
+	// Err is the error of the request, or ErrNotSubmitted if the chunk was not sent
+	// because another chunk had already failed.
+	Err error
+}
+
+// SubmitWorkCardChunks splits the work cards into chunks according to the
+// MaxCardsPerRequest and MaxRequestBytes settings and submits them, up to
+// MaxConcurrentRequests at a time. A CompanyWorkCard whose entries do not fit in
+// one chunk is split into several CompanyWorkCards with the same header. Once a
+// chunk fails no further chunks are started, but requests already in flight are
+// left to complete, since Ergani may have accepted them. All chunks are returned
+// in input order together with the error of the chunk that failed first.
+func (c *Client) SubmitWorkCardChunks(ctx context.Context, companyWorkCards []CompanyWorkCard) ([]WorkCardChunk, error) {
+	companyWorkCards = c.applyLateDeclarationPolicy(ctx, companyWorkCards)
+	parts, err := chunkWorkCards(companyWorkCards, c.maxCardsPerRequest, c.maxRequestBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	stopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil && ctx.Err() != nil {
+			firstErr = ctx.Err()
+		}
+		return firstErr != nil
+	}
+
+	chunks := make([]WorkCardChunk, len(parts))
+	sem := make(chan struct{}, c.maxConcurrentRequests)
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		chunks[i] = WorkCardChunk{Index: i, CompanyWorkCards: part}
+
+		sem <- struct{}{}
+		if stopped() {
+			<-sem
+			chunks[i].Err = ErrNotSubmitted
+			continue
+		}
+
+		wg.Add(1)
+		go func(chunk *WorkCardChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			chunk.Responses, chunk.Err = c.submitWorkCardRequest(ctx, chunk.CompanyWorkCards)
+			if chunk.Err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = chunk.Err
+				}
+				mu.Unlock()
+			}
+		}(&chunks[i])
+	}
+	wg.Wait()
+
+	return chunks, firstErr
+}
+
+// chunkWorkCards splits work cards so that every chunk holds at most maxCards
+// entries and encodes to at most maxBytes. Zero limits are ignored. Sizes are
+// computed from the encoded parts of the payload, so the check is exact.
+func chunkWorkCards(companyWorkCards []CompanyWorkCard, maxCards, maxBytes int) ([][]CompanyWorkCard, error) {
+	envelope, err := json.Marshal(map[string]map[string][]CompanyWorkCard{"Cards": {"Card": {}}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+	}
+
+	var (
+		chunks  [][]CompanyWorkCard
+		current []CompanyWorkCard
+		size    = len(envelope)
+		count   int
+	)
+	flush := func() {
+		chunks = append(chunks, current)
+		current, size, count = nil, len(envelope), 0
+	}
+	fits := func(extra int) bool {
+		return (maxCards == 0 || count+1 <= maxCards) && (maxBytes == 0 || size+extra <= maxBytes)
+	}
+
+	for _, cwc := range companyWorkCards {
+		header := cwc
+		header.CardDetails = []WorkCard{}
+		headerBytes, err := json.Marshal(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+		}
+
+		if len(cwc.CardDetails) == 0 {
+			extra := len(headerBytes)
+			if len(current) > 0 {
+				extra++
+			}
+			if len(current) > 0 && maxBytes > 0 && size+extra > maxBytes {
+				flush()
+				extra = len(headerBytes)
+			}
+			current = append(current, header)
+			size += extra
+			continue
+		}
+
+		open := false
+		for _, card := range cwc.CardDetails {
+			cardBytes, err := json.Marshal(card)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal request payload: %w", err)
+			}
+
+			extra := len(cardBytes) + 1
+			if !open {
+				extra = len(headerBytes) + len(cardBytes)
+				if len(current) > 0 {
+					extra++
+				}
+			}
+			if len(current) > 0 && !fits(extra) {
+				flush()
+				open = false
+				extra = len(headerBytes) + len(cardBytes)
+			}
+			if maxBytes > 0 && size+extra > maxBytes {
+				return nil, fmt.Errorf("work card of %s does not fit in a request of %d bytes", card.EmployeeTaxID, maxBytes)
+			}
+
+			if !open {
+				part := header
+				part.CardDetails = nil
+				current = append(current, part)
+				open = true
+			}
+			last := &current[len(current)-1]
+			last.CardDetails = append(last.CardDetails, card)
+			size += extra
+			count++
+		}
+	}
+	if len(current) > 0 {
+		flush()
+	}
+
+	return chunks, nil
+}
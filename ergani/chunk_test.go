@@ -0,0 +1,182 @@
+package ergani
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCompanyWorkCards(branches, cardsPerBranch int) []CompanyWorkCard {
+	var companies []CompanyWorkCard
+	for b := 0; b < branches; b++ {
+		cwc := CompanyWorkCard{EmployerTaxID: "999999999", BusinessBranchNumber: b}
+		for i := 0; i < cardsPerBranch; i++ {
+			cwc.CardDetails = append(cwc.CardDetails, WorkCard{
+				EmployeeTaxID:            fmt.Sprintf("%09d", b*1000+i),
+				EmployeeLastName:         "Doe",
+				EmployeeFirstName:        "John",
+				WorkCardMovementType:     Arrival,
+				WorkCardSubmissionDate:   Date{Time: time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)},
+				WorkCardMovementDateTime: DateTime{Time: time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)},
+			})
+		}
+		companies = append(companies, cwc)
+	}
+	return companies
+}
+
+func TestChunkWorkCards(t *testing.T) {
+	companies := testCompanyWorkCards(3, 7)
+
+	t.Run("ByCount", func(t *testing.T) {
+		chunks, err := chunkWorkCards(companies, 5, 0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(chunks) != 5 {
+			t.Fatalf("Expected 5 chunks for 21 cards, got %d", len(chunks))
+		}
+
+		var ids []string
+		for _, chunk := range chunks {
+			count := 0
+			for _, cwc := range chunk {
+				count += len(cwc.CardDetails)
+				for _, card := range cwc.CardDetails {
+					ids = append(ids, card.EmployeeTaxID)
+				}
+			}
+			if count > 5 {
+				t.Errorf("Expected at most 5 cards per chunk, got %d", count)
+			}
+		}
+		if len(ids) != 21 || ids[0] != "000000000" || ids[20] != "000002006" {
+			t.Errorf("Expected all cards to be kept in order, got %v", ids)
+		}
+	})
+
+	t.Run("ByBytes", func(t *testing.T) {
+		const maxBytes = 1000
+		chunks, err := chunkWorkCards(companies, 0, maxBytes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(chunks) < 2 {
+			t.Fatalf("Expected the submission to be split, got %d chunk(s)", len(chunks))
+		}
+		for i, chunk := range chunks {
+			payload, err := json.Marshal(map[string]map[string][]CompanyWorkCard{"Cards": {"Card": chunk}})
+			if err != nil {
+				t.Fatalf("Failed to marshal chunk: %v", err)
+			}
+			if len(payload) > maxBytes {
+				t.Errorf("Expected chunk %d to be at most %d bytes, got %d", i, maxBytes, len(payload))
+			}
+		}
+	})
+
+	t.Run("CardTooLarge", func(t *testing.T) {
+		if _, err := chunkWorkCards(companies, 0, 100); err == nil {
+			t.Error("Expected an error when a single card exceeds the size limit")
+		}
+	})
+}
+
+func TestSubmitWorkCard_Chunked(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{
+		Username:              "testuser",
+		Password:              "testpass",
+		BaseURL:               server.URL,
+		MaxCardsPerRequest:    2,
+		MaxConcurrentRequests: 3,
+	})
+
+	responses, err := client.SubmitWorkCard(context.Background(), testCompanyWorkCards(2, 3))
+	if err != nil {
+		t.Fatalf("Expected no error on chunked SubmitWorkCard, but got: %v", err)
+	}
+	if len(responses) != 3 {
+		t.Errorf("Expected 3 merged responses for 3 chunks, got %d", len(responses))
+	}
+}
+
+func TestSubmitWorkCardChunks_Failure(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{
+		Username:           "testuser",
+		Password:           "testpass",
+		BaseURL:            server.URL,
+		MaxCardsPerRequest: 3,
+	})
+
+	companies := testCompanyWorkCards(3, 3)
+	companies[1].Comments = "FORCE_ERROR"
+
+	chunks, err := client.SubmitWorkCardChunks(context.Background(), companies)
+	if _, ok := err.(*APIError); !ok {
+		t.Fatalf("Expected error to be of type APIError, but got %T", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Err != nil || len(chunks[0].Responses) != 1 {
+		t.Errorf("Expected the first chunk to be accepted, got %v", chunks[0].Err)
+	}
+	if chunks[2].Err != ErrNotSubmitted {
+		t.Errorf("Expected the last chunk not to be submitted, got %v", chunks[2].Err)
+	}
+}
+
+func TestSubmitWorkCardChunks_FailureInFlight(t *testing.T) {
+	slowStarted, failed := make(chan struct{}), make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Authentication", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accessToken": "test-token"}`))
+	})
+	mux.HandleFunc("/Documents/WRKCardSE", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "SLOW"):
+			close(slowStarted)
+			// Stay in flight until the client has seen the failure.
+			<-failed
+			time.Sleep(100 * time.Millisecond)
+		case strings.Contains(string(body), "FORCE_ERROR"):
+			<-slowStarted
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"msg": "Invalid data provided"}`))
+			close(failed)
+			return
+		}
+		w.Write([]byte(`[{"id": "sub123", "protocol": "proto456", "submitDate": "10/07/2025 14:56"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{BaseURL: server.URL, MaxCardsPerRequest: 3, MaxConcurrentRequests: 2})
+	companies := testCompanyWorkCards(3, 3)
+	companies[0].Comments = "SLOW"
+	companies[1].Comments = "FORCE_ERROR"
+
+	chunks, err := client.SubmitWorkCardChunks(context.Background(), companies)
+	if _, ok := err.(*APIError); !ok {
+		t.Fatalf("Expected the error of the failed chunk, got %v", err)
+	}
+	if chunks[0].Err != nil || len(chunks[0].Responses) != 1 {
+		t.Errorf("Expected the request in flight to complete, got %v", chunks[0].Err)
+	}
+	if chunks[2].Err != ErrNotSubmitted {
+		t.Errorf("Expected the last chunk not to be submitted, got %v", chunks[2].Err)
+	}
+}
@@ -0,0 +1,183 @@
+// Package compliance checks work time schedules and overtime against Greek
+// labour-law limits before they are submitted.
+//
+// An Engine expands the declared schedules and overtime into a Timeline of work
+// periods per employee and runs a set of rules over it. Each rule reports findings
+// with a severity and a reference to the offending row; limits and severities are
+// configured per sector through a Profile.
+package compliance
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Severity is the importance of a finding.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// String implements the fmt.Stringer interface.
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "INFO"
+	case Warning:
+		return "WARNING"
+	case Error:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// RowRef points at an employee row of an input document.
+type RowRef struct {
+	Document ergani.DocumentType
+	// Index is the position of the company document in its input slice.
+	Index int
+	// Row is the position of the employee row within the document.
+	Row int
+}
+
+// String implements the fmt.Stringer interface.
+func (r RowRef) String() string {
+	return fmt.Sprintf("%s[%d] row %d", r.Document, r.Index, r.Row)
+}
+
+// Finding is a rule violation.
+type Finding struct {
+	Rule          string
+	Severity      Severity
+	EmployeeTaxID string
+	Date          ergani.Date
+	Message       string
+	// Refs are the rows that cause the violation.
+	Refs []RowRef
+}
+
+// String implements the fmt.Stringer interface.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s: %s on %s: %s", f.Severity, f.Rule, f.EmployeeTaxID, f.Date.Format("02/01/2006"), f.Message)
+}
+
+// Profile holds the limits and severities that apply to a sector.
+type Profile struct {
+	Name string
+	// MinDailyRest is the minimum rest between two working days.
+	MinDailyRest time.Duration
+	// MinWeeklyRest is the minimum uninterrupted rest within every week.
+	MinWeeklyRest time.Duration
+	// MaxDailyWork is the maximum working time per day, including overtime.
+	MaxDailyWork time.Duration
+	// AnnualOvertimeCap is the maximum overtime per employee and calendar year.
+	AnnualOvertimeCap time.Duration
+	// BreakAfter is the continuous working time after which a break is due.
+	BreakAfter time.Duration
+	// MinBreak is the minimum break that interrupts continuous work.
+	MinBreak time.Duration
+	// Severities overrides the default severity of rules by name.
+	Severities map[string]Severity
+	// Disabled skips rules by name.
+	Disabled map[string]bool
+}
+
+// DefaultAnnualOvertimeCap is the statutory annual overtime cap per employee.
+const DefaultAnnualOvertimeCap = 150 * time.Hour
+
+// DefaultProfile holds the general limits for employees in the private sector.
+var DefaultProfile = Profile{
+	Name:              "default",
+	MinDailyRest:      11 * time.Hour,
+	MinWeeklyRest:     24 * time.Hour,
+	MaxDailyWork:      13 * time.Hour,
+	AnnualOvertimeCap: DefaultAnnualOvertimeCap,
+	BreakAfter:        6 * time.Hour,
+	MinBreak:          20 * time.Minute,
+}
+
+// Input holds the documents to check.
+type Input struct {
+	DailySchedules  []ergani.CompanyDailySchedule
+	WeeklySchedules []ergani.CompanyWeeklySchedule
+	Overtimes       []ergani.CompanyOvertime
+	// OvertimeUsed, if set, returns the overtime an employee has already worked in
+	// a year outside the input, e.g. from a quota store.
+	OvertimeUsed func(taxID string, year int) time.Duration
+	// Location is used to resolve dates. Defaults to time.Local.
+	Location *time.Location
+}
+
+// Rule checks a timeline against a profile.
+type Rule interface {
+	// Name identifies the rule in findings and in Profile.Severities and Disabled.
+	Name() string
+	// Severity is the default severity of the rule's findings.
+	Severity() Severity
+	Check(t *Timeline, p Profile) []Finding
+}
+
+// Engine runs rules against documents.
+type Engine struct {
+	profile Profile
+	rules   []Rule
+}
+
+// NewEngine creates an Engine for the profile. Without rules DefaultRules is used.
+func NewEngine(profile Profile, rules ...Rule) *Engine {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &Engine{profile: profile, rules: rules}
+}
+
+// Check runs all enabled rules and returns their findings ordered by employee,
+// date and rule.
+func (e *Engine) Check(in Input) []Finding {
+	timeline := NewTimeline(in)
+
+	var findings []Finding
+	for _, rule := range e.rules {
+		if e.profile.Disabled[rule.Name()] {
+			continue
+		}
+		severity := rule.Severity()
+		if s, ok := e.profile.Severities[rule.Name()]; ok {
+			severity = s
+		}
+		for _, f := range rule.Check(timeline, e.profile) {
+			f.Rule = rule.Name()
+			f.Severity = severity
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.EmployeeTaxID != b.EmployeeTaxID {
+			return a.EmployeeTaxID < b.EmployeeTaxID
+		}
+		if !a.Date.Equal(b.Date.Time) {
+			return a.Date.Before(b.Date.Time)
+		}
+		return a.Rule < b.Rule
+	})
+	return findings
+}
+
+// HasErrors reports whether any finding has Error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity >= Error {
+			return true
+		}
+	}
+	return false
+}
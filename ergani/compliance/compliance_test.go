@@ -0,0 +1,95 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+func TestEngine(t *testing.T) {
+	monday := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+
+	weekly := ergani.CompanyWeeklySchedule{
+		StartDate: ergani.Date{Time: monday},
+		EndDate:   ergani.Date{Time: monday.AddDate(0, 0, 6)},
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		weekly.EmployeeSchedules = append(weekly.EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+			EmployeeTaxID:  "111111111",
+			ScheduleDay:    ergani.Weekday{Weekday: d},
+			WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "09:00", "17:00")},
+		})
+	}
+	for d := time.Monday; d <= time.Friday; d++ {
+		weekly.EmployeeSchedules = append(weekly.EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+			EmployeeTaxID:  "222222222",
+			ScheduleDay:    ergani.Weekday{Weekday: d},
+			WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00"), testutil.Workday(t, ergani.WorkFromOffice, "13:10", "17:00")},
+		})
+	}
+
+	// A late evening on Tuesday cuts the rest before Wednesday's start.
+	daily := ergani.CompanyDailySchedule{EmployeeSchedules: []ergani.EmployeeDailySchedule{
+		{EmployeeTaxID: "222222222", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 1)}, WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00"), testutil.Workday(t, ergani.WorkFromOffice, "17:00", "23:00")}},
+	}}
+
+	overtime := ergani.CompanyOvertime{EmployeeOvertimes: []ergani.Overtime{
+		{EmployeeTaxID: "222222222", OvertimeDate: ergani.Date{Time: monday}, OvertimeStartTime: testutil.Clock(t, "17:00"), OvertimeEndTime: testutil.Clock(t, "23:00"), WeeklyWorkdaysNumber: 5},
+		{EmployeeTaxID: "222222222", OvertimeDate: ergani.Date{Time: monday.AddDate(0, 0, 5)}, OvertimeStartTime: testutil.Clock(t, "09:00"), OvertimeEndTime: testutil.Clock(t, "13:00"), WeeklyWorkdaysNumber: 5},
+	}}
+
+	in := Input{
+		DailySchedules:  []ergani.CompanyDailySchedule{daily},
+		WeeklySchedules: []ergani.CompanyWeeklySchedule{weekly},
+		Overtimes:       []ergani.CompanyOvertime{overtime},
+		OvertimeUsed:    func(string, int) time.Duration { return 145 * time.Hour },
+		Location:        time.UTC,
+	}
+
+	findings := NewEngine(DefaultProfile).Check(in)
+
+	find := func(taxID, rule string, day int) *Finding {
+		for i, f := range findings {
+			if f.EmployeeTaxID == taxID && f.Rule == rule && f.Date.Equal(monday.AddDate(0, 0, day)) {
+				return &findings[i]
+			}
+		}
+		return nil
+	}
+
+	if f := find("111111111", WeeklyRestRule, 0); f == nil || f.Severity != Error {
+		t.Errorf("Expected a weekly rest violation for a seven-day week, got %v", findings)
+	}
+	if f := find("111111111", BreakRule, 0); f == nil || f.Severity != Warning || f.Refs[0].Document != ergani.WeeklyScheduleDocument {
+		t.Errorf("Expected a break warning for 8 continuous hours, got %v", findings)
+	}
+	if f := find("222222222", BreakRule, 1); f != nil {
+		t.Errorf("Expected a 4 hour split to need no break, got %v", f)
+	}
+	if f := find("222222222", DailyRestRule, 2); f == nil || f.Refs[0].Document != ergani.DailyScheduleDocument {
+		t.Errorf("Expected a daily rest violation after Tuesday's late shift, got %v", findings)
+	}
+	if f := find("222222222", MaxDailyWorkRule, 0); f == nil || len(f.Refs) != 3 {
+		t.Errorf("Expected Monday with overtime to exceed the daily maximum, got %v", findings)
+	}
+	if f := find("222222222", AnnualOvertimeCapRule, 0); f == nil || f.Refs[0] != (RowRef{Document: ergani.OvertimeDocument, Index: 0, Row: 0}) {
+		t.Errorf("Expected the first overtime row to exceed the annual cap, got %v", findings)
+	}
+	if f := find("222222222", WeeklyWorkdaysRule, 5); f == nil || f.Refs[0].Row != 1 {
+		t.Errorf("Expected Saturday overtime to exceed a 5-day week, got %v", findings)
+	}
+
+	profile := DefaultProfile
+	profile.Disabled = map[string]bool{BreakRule: true}
+	profile.Severities = map[string]Severity{WeeklyRestRule: Warning}
+	for _, f := range NewEngine(profile).Check(in) {
+		if f.Rule == BreakRule {
+			t.Errorf("Expected disabled rule to be skipped, got %v", f)
+		}
+		if f.Rule == WeeklyRestRule && f.Severity != Warning {
+			t.Errorf("Expected severity override, got %v", f)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package compliance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Rule names.
+const (
+	DailyRestRule         = "daily-rest"
+	WeeklyRestRule        = "weekly-rest"
+	MaxDailyWorkRule      = "max-daily-work"
+	AnnualOvertimeCapRule = "annual-overtime-cap"
+	WeeklyWorkdaysRule    = "weekly-workdays"
+	BreakRule             = "break"
+)
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		dailyRest{},
+		weeklyRest{},
+		maxDailyWork{},
+		annualOvertimeCap{},
+		weeklyWorkdays{},
+		breaks{},
+	}
+}
+
+// dailyRest requires MinDailyRest between the end of a working day and the start
+// of the next one.
+type dailyRest struct{}
+
+func (dailyRest) Name() string       { return DailyRestRule }
+func (dailyRest) Severity() Severity { return Error }
+
+func (dailyRest) Check(t *Timeline, p Profile) []Finding {
+	var findings []Finding
+	for _, taxID := range t.Employees() {
+		days := t.Days(taxID)
+		for i := 1; i < len(days); i++ {
+			rest := days[i].First().Sub(days[i-1].Last())
+			if rest < p.MinDailyRest {
+				findings = append(findings, Finding{
+					EmployeeTaxID: taxID,
+					Date:          days[i].Date,
+					Message:       fmt.Sprintf("only %v of rest since the previous working day, at least %v required", rest, p.MinDailyRest),
+					Refs:          []RowRef{days[i-1].Periods[len(days[i-1].Periods)-1].Ref, days[i].Periods[0].Ref},
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// weeklyRest requires an uninterrupted rest of MinWeeklyRest in every calendar
+// week (Monday to Sunday) that contains work.
+type weeklyRest struct{}
+
+func (weeklyRest) Name() string       { return WeeklyRestRule }
+func (weeklyRest) Severity() Severity { return Error }
+
+func (weeklyRest) Check(t *Timeline, p Profile) []Finding {
+	var findings []Finding
+	for _, taxID := range t.Employees() {
+		periods := t.Periods(taxID)
+		for i := 0; i < len(periods); {
+			weekStart := startOfWeek(periods[i].Start.In(t.Location()))
+			weekEnd := weekStart.AddDate(0, 0, 7)
+
+			var (
+				longest time.Duration
+				refs    []RowRef
+			)
+			cursor := weekStart
+			j := i
+			for ; j < len(periods) && periods[j].Start.Before(weekEnd); j++ {
+				if gap := periods[j].Start.Sub(cursor); gap > longest {
+					longest = gap
+				}
+				if periods[j].End.After(cursor) {
+					cursor = periods[j].End
+				}
+				refs = append(refs, periods[j].Ref)
+			}
+			if gap := weekEnd.Sub(cursor); gap > longest {
+				longest = gap
+			}
+
+			if longest < p.MinWeeklyRest {
+				findings = append(findings, Finding{
+					EmployeeTaxID: taxID,
+					Date:          ergani.Date{Time: weekStart},
+					Message:       fmt.Sprintf("longest rest in the week is %v, at least %v required", longest, p.MinWeeklyRest),
+					Refs:          refs,
+				})
+			}
+			i = j
+		}
+	}
+	return findings
+}
+
+// maxDailyWork limits the working time of a day, including overtime.
+type maxDailyWork struct{}
+
+func (maxDailyWork) Name() string       { return MaxDailyWorkRule }
+func (maxDailyWork) Severity() Severity { return Error }
+
+func (maxDailyWork) Check(t *Timeline, p Profile) []Finding {
+	var findings []Finding
+	for _, taxID := range t.Employees() {
+		for _, day := range t.Days(taxID) {
+			if worked := day.Worked(); worked > p.MaxDailyWork {
+				findings = append(findings, Finding{
+					EmployeeTaxID: taxID,
+					Date:          day.Date,
+					Message:       fmt.Sprintf("%v of work declared, at most %v allowed", worked, p.MaxDailyWork),
+					Refs:          day.Refs(),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// annualOvertimeCap limits the overtime of an employee per calendar year. The
+// finding points at the overtime row that exceeds the cap.
+type annualOvertimeCap struct{}
+
+func (annualOvertimeCap) Name() string       { return AnnualOvertimeCapRule }
+func (annualOvertimeCap) Severity() Severity { return Error }
+
+func (annualOvertimeCap) Check(t *Timeline, p Profile) []Finding {
+	var findings []Finding
+	for _, taxID := range t.Employees() {
+		used := make(map[int]time.Duration)
+		reported := make(map[int]bool)
+		for _, period := range t.Periods(taxID) {
+			if !period.Overtime {
+				continue
+			}
+			year := period.Date.Year()
+			if _, ok := used[year]; !ok {
+				used[year] = t.OvertimeUsed(taxID, year)
+			}
+			used[year] += period.Duration()
+			if used[year] > p.AnnualOvertimeCap && !reported[year] {
+				reported[year] = true
+				findings = append(findings, Finding{
+					EmployeeTaxID: taxID,
+					Date:          period.Date,
+					Message:       fmt.Sprintf("annual overtime reaches %v, at most %v allowed in %d", used[year], p.AnnualOvertimeCap, year),
+					Refs:          []RowRef{period.Ref},
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// weeklyWorkdays checks that the WeeklyWorkdaysNumber of overtime rows is 5 or 6
+// and that the week does not contain more working days than it declares.
+type weeklyWorkdays struct{}
+
+func (weeklyWorkdays) Name() string       { return WeeklyWorkdaysRule }
+func (weeklyWorkdays) Severity() Severity { return Error }
+
+func (weeklyWorkdays) Check(t *Timeline, _ Profile) []Finding {
+	var findings []Finding
+	for _, taxID := range t.Employees() {
+		workdays := make(map[time.Time]int)
+		for _, day := range t.Days(taxID) {
+			workdays[startOfWeek(day.Date.Time)]++
+		}
+
+		for _, period := range t.Periods(taxID) {
+			if !period.Overtime {
+				continue
+			}
+			if period.WeeklyWorkdays != 5 && period.WeeklyWorkdays != 6 {
+				findings = append(findings, Finding{
+					EmployeeTaxID: taxID,
+					Date:          period.Date,
+					Message:       fmt.Sprintf("WeeklyWorkdaysNumber must be 5 or 6, got %d", period.WeeklyWorkdays),
+					Refs:          []RowRef{period.Ref},
+				})
+				continue
+			}
+			if n := workdays[startOfWeek(period.Date.Time)]; n > period.WeeklyWorkdays {
+				findings = append(findings, Finding{
+					EmployeeTaxID: taxID,
+					Date:          period.Date,
+					Message:       fmt.Sprintf("%d working days declared in a %d-day week", n, period.WeeklyWorkdays),
+					Refs:          []RowRef{period.Ref},
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// breaks flags continuous work longer than BreakAfter that is not interrupted by
+// at least MinBreak, since the break then has to be granted within the period.
+type breaks struct{}
+
+func (breaks) Name() string       { return BreakRule }
+func (breaks) Severity() Severity { return Warning }
+
+func (breaks) Check(t *Timeline, p Profile) []Finding {
+	var findings []Finding
+	for _, taxID := range t.Employees() {
+		for _, day := range t.Days(taxID) {
+			start, end := day.Periods[0].Start, day.Periods[0].End
+			refs := []RowRef{day.Periods[0].Ref}
+			flush := func() {
+				if continuous := end.Sub(start); continuous > p.BreakAfter {
+					findings = append(findings, Finding{
+						EmployeeTaxID: taxID,
+						Date:          day.Date,
+						Message:       fmt.Sprintf("%v of continuous work, a break of at least %v is required", continuous, p.MinBreak),
+						Refs:          refs,
+					})
+				}
+			}
+			for _, period := range day.Periods[1:] {
+				if period.Start.Sub(end) >= p.MinBreak {
+					flush()
+					start, refs = period.Start, nil
+				}
+				if period.End.After(end) {
+					end = period.End
+				}
+				refs = append(refs, period.Ref)
+			}
+			flush()
+		}
+	}
+	return findings
+}
+
+func startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
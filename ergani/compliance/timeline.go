@@ -0,0 +1,217 @@
+package compliance
+
+import (
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Period is a declared working or overtime period of an employee.
+type Period struct {
+	EmployeeTaxID string
+	// Date is the day the period was declared for.
+	Date     ergani.Date
+	Start    time.Time
+	End      time.Time
+	Overtime bool
+	// WeeklyWorkdays is the WeeklyWorkdaysNumber of an overtime period.
+	WeeklyWorkdays int
+	Ref            RowRef
+}
+
+// Duration returns the length of the period.
+func (p Period) Duration() time.Duration {
+	return p.End.Sub(p.Start)
+}
+
+// Day groups the periods of an employee declared for one date.
+type Day struct {
+	Date    ergani.Date
+	Periods []Period
+}
+
+// Worked returns the total working time of the day, including overtime.
+func (d Day) Worked() time.Duration {
+	var total time.Duration
+	for _, p := range d.Periods {
+		total += p.Duration()
+	}
+	return total
+}
+
+// First returns the start of the first period of the day.
+func (d Day) First() time.Time {
+	return d.Periods[0].Start
+}
+
+// Last returns the end of the last period of the day.
+func (d Day) Last() time.Time {
+	last := d.Periods[0].End
+	for _, p := range d.Periods[1:] {
+		if p.End.After(last) {
+			last = p.End
+		}
+	}
+	return last
+}
+
+// Refs returns the rows of the day's periods.
+func (d Day) Refs() []RowRef {
+	refs := make([]RowRef, len(d.Periods))
+	for i, p := range d.Periods {
+		refs[i] = p.Ref
+	}
+	return refs
+}
+
+// Timeline holds the work periods of every employee, with weekly schedules
+// expanded over their validity range. A daily schedule replaces the weekly entries
+// of its employee and date, and a later weekly schedule replaces an earlier one.
+type Timeline struct {
+	location     *time.Location
+	periods      map[string][]Period
+	overtimeUsed func(taxID string, year int) time.Duration
+}
+
+type dayKey struct {
+	taxID string
+	day   string
+}
+
+// NewTimeline expands the input documents into a Timeline.
+func NewTimeline(in Input) *Timeline {
+	loc := in.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t := &Timeline{location: loc, periods: make(map[string][]Period), overtimeUsed: in.OvertimeUsed}
+
+	claimed := make(map[dayKey]bool)
+	for i, schedule := range in.DailySchedules {
+		for row, es := range schedule.EmployeeSchedules {
+			date := t.day(es.ScheduleDate.Time)
+			claimed[dayKey{es.EmployeeTaxID, date.Format("2006-01-02")}] = true
+			t.addWork(es.EmployeeTaxID, date, es.WorkdayDetails, RowRef{Document: ergani.DailyScheduleDocument, Index: i, Row: row})
+		}
+	}
+
+	for i := len(in.WeeklySchedules) - 1; i >= 0; i-- {
+		schedule := in.WeeklySchedules[i]
+		weekClaimed := make(map[dayKey]bool)
+		end := t.day(schedule.EndDate.Time)
+		for date := t.day(schedule.StartDate.Time); !date.After(end); date = date.AddDate(0, 0, 1) {
+			for row, es := range schedule.EmployeeSchedules {
+				if es.ScheduleDay.Weekday != date.Weekday() {
+					continue
+				}
+				key := dayKey{es.EmployeeTaxID, date.Format("2006-01-02")}
+				if claimed[key] {
+					continue
+				}
+				weekClaimed[key] = true
+				t.addWork(es.EmployeeTaxID, date, es.WorkdayDetails, RowRef{Document: ergani.WeeklyScheduleDocument, Index: i, Row: row})
+			}
+		}
+		for key := range weekClaimed {
+			claimed[key] = true
+		}
+	}
+
+	for i, doc := range in.Overtimes {
+		for row, ot := range doc.EmployeeOvertimes {
+			if ot.OvertimeCancellation {
+				continue
+			}
+			date := t.day(ot.OvertimeDate.Time)
+			wd := ergani.WorkdayDetails{StartTime: ot.OvertimeStartTime, EndTime: ot.OvertimeEndTime}
+			start, end := wd.On(date)
+			t.periods[ot.EmployeeTaxID] = append(t.periods[ot.EmployeeTaxID], Period{
+				EmployeeTaxID:  ot.EmployeeTaxID,
+				Date:           ergani.Date{Time: date},
+				Start:          start,
+				End:            end,
+				Overtime:       true,
+				WeeklyWorkdays: ot.WeeklyWorkdaysNumber,
+				Ref:            RowRef{Document: ergani.OvertimeDocument, Index: i, Row: row},
+			})
+		}
+	}
+
+	for taxID, periods := range t.periods {
+		sort.SliceStable(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+		t.periods[taxID] = periods
+	}
+	return t
+}
+
+func (t *Timeline) addWork(taxID string, date time.Time, details []ergani.WorkdayDetails, ref RowRef) {
+	for _, wd := range details {
+		if !wd.WorkType.IsWork() {
+			continue
+		}
+		start, end := wd.On(date)
+		t.periods[taxID] = append(t.periods[taxID], Period{
+			EmployeeTaxID: taxID,
+			Date:          ergani.Date{Time: date},
+			Start:         start,
+			End:           end,
+			Ref:           ref,
+		})
+	}
+}
+
+// day returns midnight of the calendar date of d in the timeline's location.
+func (t *Timeline) day(d time.Time) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, t.location)
+}
+
+// Location returns the location dates are resolved in.
+func (t *Timeline) Location() *time.Location {
+	return t.location
+}
+
+// Employees returns the tax IDs of all employees in the timeline, sorted.
+func (t *Timeline) Employees() []string {
+	taxIDs := make([]string, 0, len(t.periods))
+	for taxID := range t.periods {
+		taxIDs = append(taxIDs, taxID)
+	}
+	sort.Strings(taxIDs)
+	return taxIDs
+}
+
+// Periods returns the periods of an employee ordered by start time.
+func (t *Timeline) Periods(taxID string) []Period {
+	return t.periods[taxID]
+}
+
+// Days returns the periods of an employee grouped by declared date, in date order.
+func (t *Timeline) Days(taxID string) []Day {
+	byDate := make(map[time.Time]*Day)
+	var dates []time.Time
+	for _, p := range t.periods[taxID] {
+		d, ok := byDate[p.Date.Time]
+		if !ok {
+			d = &Day{Date: p.Date}
+			byDate[p.Date.Time] = d
+			dates = append(dates, p.Date.Time)
+		}
+		d.Periods = append(d.Periods, p)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	days := make([]Day, len(dates))
+	for i, date := range dates {
+		days[i] = *byDate[date]
+	}
+	return days
+}
+
+// OvertimeUsed returns the overtime worked outside the input, or zero if unknown.
+func (t *Timeline) OvertimeUsed(taxID string, year int) time.Duration {
+	if t.overtimeUsed == nil {
+		return 0
+	}
+	return t.overtimeUsed(taxID, year)
+}
@@ -0,0 +1,101 @@
+package ergani
+
+import (
+	"fmt"
+	"time"
+)
+
+// NewCompanyWorkCardCorrection returns a correction document for a business branch
+// that references the protocol of the original work card submission.
+func NewCompanyWorkCardCorrection(original SubmissionResponse, employerTaxID string, businessBranchNumber int, corrections ...WorkCardCorrection) CompanyWorkCardCorrection {
+	return CompanyWorkCardCorrection{
+		EmployerTaxID:        employerTaxID,
+		BusinessBranchNumber: businessBranchNumber,
+		RelatedProtocolID:    original.Protocol,
+		RelatedProtocolDate:  Date{Time: original.SubmissionDate},
+		Corrections:          corrections,
+	}
+}
+
+// Correct returns a correction of the submitted work card entry that moves it to
+// the given time.
+func (wc WorkCard) Correct(to time.Time, justification CorrectionJustificationType) WorkCardCorrection {
+	return WorkCardCorrection{
+		EmployeeTaxID:             wc.EmployeeTaxID,
+		EmployeeLastName:          wc.EmployeeLastName,
+		EmployeeFirstName:         wc.EmployeeFirstName,
+		CorrectionType:            Correction,
+		WorkCardMovementType:      wc.WorkCardMovementType,
+		OriginalMovementDateTime:  wc.WorkCardMovementDateTime,
+		CorrectedMovementDateTime: &DateTime{Time: to},
+		Justification:             justification,
+	}
+}
+
+// Cancel returns a cancellation of the submitted work card entry.
+func (wc WorkCard) Cancel(justification CorrectionJustificationType) WorkCardCorrection {
+	return WorkCardCorrection{
+		EmployeeTaxID:            wc.EmployeeTaxID,
+		EmployeeLastName:         wc.EmployeeLastName,
+		EmployeeFirstName:        wc.EmployeeFirstName,
+		CorrectionType:           Cancellation,
+		WorkCardMovementType:     wc.WorkCardMovementType,
+		OriginalMovementDateTime: wc.WorkCardMovementDateTime,
+		Justification:            justification,
+	}
+}
+
+// NewOvertimeCancellation builds the cancellation of a previously submitted overtime
+// document. The submitted CompanyOvertime must be the one that produced the original
+// response (e.g., as kept in a local receipt store). When rows are given only the
+// matching employee rows are cancelled; otherwise every row is. Rows are matched by
+// employee tax ID, date and time range and are copied from the submitted document so
+// that the cancellation carries exactly the same data.
+func NewOvertimeCancellation(original SubmissionResponse, submitted CompanyOvertime, rows ...Overtime) (CompanyOvertime, error) {
+	if original.Protocol == "" {
+		return CompanyOvertime{}, &ValidationError{Field: "Protocol", Message: "the original submission has no protocol"}
+	}
+
+	selected := submitted.EmployeeOvertimes
+	if len(rows) > 0 {
+		selected = make([]Overtime, 0, len(rows))
+		for _, row := range rows {
+			match, ok := findOvertimeRow(submitted.EmployeeOvertimes, row)
+			if !ok {
+				return CompanyOvertime{}, &ValidationError{
+					Field:   "EmployeeOvertimes",
+					Message: fmt.Sprintf("overtime of %s on %s was not part of submission %s", row.EmployeeTaxID, row.OvertimeDate.Format("02/01/2006"), original.Protocol),
+				}
+			}
+			selected = append(selected, match)
+		}
+	}
+	if len(selected) == 0 {
+		return CompanyOvertime{}, &ValidationError{Field: "EmployeeOvertimes", Message: "there are no overtime rows to cancel"}
+	}
+
+	cancellation := submitted
+	cancellation.EmployeeOvertimes = make([]Overtime, len(selected))
+	for i, row := range selected {
+		row.OvertimeCancellation = true
+		cancellation.EmployeeOvertimes[i] = row
+	}
+	cancellation.RelatedProtocolID = original.Protocol
+	cancellation.RelatedProtocolDate = &Date{Time: original.SubmissionDate}
+
+	return cancellation, nil
+}
+
+// findOvertimeRow returns the row of rows that refers to the same employee, date and
+// time range as row.
+func findOvertimeRow(rows []Overtime, row Overtime) (Overtime, bool) {
+	for _, r := range rows {
+		if r.EmployeeTaxID == row.EmployeeTaxID &&
+			r.OvertimeDate.Format("2006-01-02") == row.OvertimeDate.Format("2006-01-02") &&
+			r.OvertimeStartTime.Format("15:04") == row.OvertimeStartTime.Format("15:04") &&
+			r.OvertimeEndTime.Format("15:04") == row.OvertimeEndTime.Format("15:04") {
+			return r, true
+		}
+	}
+	return Overtime{}, false
+}
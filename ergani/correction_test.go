@@ -0,0 +1,71 @@
+package ergani
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewOvertimeCancellation(t *testing.T) {
+	at := func(h int) Time { return Time{Time: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)} }
+	day := Date{Time: time.Date(2025, 7, 11, 0, 0, 0, 0, time.UTC)}
+
+	original := SubmissionResponse{
+		ID:             "sub456",
+		Protocol:       "proto789",
+		SubmissionDate: time.Date(2025, 7, 11, 10, 0, 0, 0, time.UTC),
+	}
+	submitted := CompanyOvertime{
+		BusinessBranchNumber: 1,
+		SEPEServiceCode:      "12345",
+		EmployeeOvertimes: []Overtime{
+			{EmployeeTaxID: "123456789", EmployeeSSN: "01017000000", OvertimeDate: day, OvertimeStartTime: at(17), OvertimeEndTime: at(19), OvertimeJustification: ExceptionalWorkload},
+			{EmployeeTaxID: "987654321", EmployeeSSN: "02027000000", OvertimeDate: day, OvertimeStartTime: at(17), OvertimeEndTime: at(18), OvertimeJustification: ExceptionalWorkload},
+		},
+	}
+
+	t.Run("All", func(t *testing.T) {
+		cancellation, err := NewOvertimeCancellation(original, submitted)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cancellation.EmployeeOvertimes) != 2 {
+			t.Fatalf("Expected 2 cancelled rows, got %d", len(cancellation.EmployeeOvertimes))
+		}
+		for _, row := range cancellation.EmployeeOvertimes {
+			if !row.OvertimeCancellation {
+				t.Errorf("Expected row of %s to be cancelled", row.EmployeeTaxID)
+			}
+		}
+		if cancellation.RelatedProtocolID != "proto789" || cancellation.RelatedProtocolDate == nil {
+			t.Errorf("Expected cancellation to reference proto789, got '%s'", cancellation.RelatedProtocolID)
+		}
+		if cancellation.SEPEServiceCode != "12345" {
+			t.Errorf("Expected branch header to be preserved, got SEPE code '%s'", cancellation.SEPEServiceCode)
+		}
+		if submitted.EmployeeOvertimes[0].OvertimeCancellation {
+			t.Error("Expected the submitted document not to be modified")
+		}
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		row := Overtime{EmployeeTaxID: "987654321", OvertimeDate: day, OvertimeStartTime: at(17), OvertimeEndTime: at(18)}
+		cancellation, err := NewOvertimeCancellation(original, submitted, row)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cancellation.EmployeeOvertimes) != 1 {
+			t.Fatalf("Expected 1 cancelled row, got %d", len(cancellation.EmployeeOvertimes))
+		}
+		if cancellation.EmployeeOvertimes[0].EmployeeSSN != "02027000000" {
+			t.Errorf("Expected the row to be copied from the submission, got SSN '%s'", cancellation.EmployeeOvertimes[0].EmployeeSSN)
+		}
+	})
+
+	t.Run("UnknownRow", func(t *testing.T) {
+		row := Overtime{EmployeeTaxID: "987654321", OvertimeDate: day, OvertimeStartTime: at(18), OvertimeEndTime: at(20)}
+		_, err := NewOvertimeCancellation(original, submitted, row)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+		}
+	})
+}
@@ -0,0 +1,403 @@
+package ergani
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupState is the recorded state of a work card fingerprint.
+type DedupState string
+
+const (
+	// DedupSending marks an entry that a call is sending. Other calls skip it
+	// until it is resolved or older than DedupClaimTimeout, e.g. because the
+	// process stopped while sending.
+	DedupSending DedupState = "SENDING"
+	// DedupPending marks an entry that was sent but whose outcome is unknown, e.g.
+	// because the request timed out.
+	DedupPending DedupState = "PENDING"
+	// DedupSubmitted marks an entry that was accepted by the API.
+	DedupSubmitted DedupState = "SUBMITTED"
+)
+
+// DedupClaimTimeout is how long an entry recorded as DedupSending is considered
+// to be in flight. Older entries are reconciled like pending ones.
+const DedupClaimTimeout = 15 * time.Minute
+
+// DedupRecord is the state of a work card entry kept by a DedupStore.
+type DedupRecord struct {
+	State    DedupState
+	Protocol string
+	// RecordedAt is when the entry was last sent or reconciled.
+	RecordedAt time.Time
+}
+
+// Equal reports whether both records hold the same state.
+func (r DedupRecord) Equal(other DedupRecord) bool {
+	return r.State == other.State && r.Protocol == other.Protocol && r.RecordedAt.Equal(other.RecordedAt)
+}
+
+// DedupStore records which work card entries were already sent, so that retries
+// after a timeout do not create duplicate movements.
+type DedupStore interface {
+	// Get returns the record of a fingerprint and whether it exists.
+	Get(ctx context.Context, fingerprint string) (DedupRecord, bool, error)
+	Put(ctx context.Context, fingerprint string, record DedupRecord) error
+	// PutIfAbsent stores record unless the fingerprint already has a record and
+	// reports whether it was stored. It must be atomic, so that concurrent calls
+	// sending the same entry claim it only once.
+	PutIfAbsent(ctx context.Context, fingerprint string, record DedupRecord) (bool, error)
+	// Replace stores record only if the current record of the fingerprint is equal
+	// to old and reports whether it was stored. It must be atomic like PutIfAbsent.
+	Replace(ctx context.Context, fingerprint string, old, record DedupRecord) (bool, error)
+	Delete(ctx context.Context, fingerprint string) error
+}
+
+// WorkCardFingerprint returns a deterministic identifier of a work card entry built
+// from the employer, branch, employee, movement type and movement time.
+func WorkCardFingerprint(employerTaxID string, businessBranchNumber int, card WorkCard) string {
+	key := strings.Join([]string{
+		employerTaxID,
+		strconv.Itoa(businessBranchNumber),
+		card.EmployeeTaxID,
+		string(card.WorkCardMovementType),
+		card.WorkCardMovementDateTime.UTC().Format(time.RFC3339),
+	}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryDedupStore is an in-memory DedupStore. Records older than its TTL, by
+// RecordedAt, are treated as absent and removed by Prune.
+type MemoryDedupStore struct {
+	ttl time.Duration
+	// now returns the current time and is replaced in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	records map[string]DedupRecord
+}
+
+// NewMemoryDedupStore creates an empty MemoryDedupStore that keeps records for
+// ttl. A zero ttl keeps them forever. The ttl should be longer than the time
+// callers keep retrying a submission and than DedupClaimTimeout.
+func NewMemoryDedupStore(ttl time.Duration) *MemoryDedupStore {
+	return &MemoryDedupStore{ttl: ttl, now: time.Now, records: make(map[string]DedupRecord)}
+}
+
+// get returns a record that has not expired. It must be called with mu held.
+func (s *MemoryDedupStore) get(fingerprint string) (DedupRecord, bool) {
+	record, ok := s.records[fingerprint]
+	if ok && s.expired(record, s.now()) {
+		return DedupRecord{}, false
+	}
+	return record, ok
+}
+
+func (s *MemoryDedupStore) expired(record DedupRecord, now time.Time) bool {
+	return s.ttl > 0 && now.Sub(record.RecordedAt) >= s.ttl
+}
+
+// Get implements DedupStore.
+func (s *MemoryDedupStore) Get(_ context.Context, fingerprint string) (DedupRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.get(fingerprint)
+	return record, ok, nil
+}
+
+// Put implements DedupStore.
+func (s *MemoryDedupStore) Put(_ context.Context, fingerprint string, record DedupRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[fingerprint] = record
+	return nil
+}
+
+// PutIfAbsent implements DedupStore.
+func (s *MemoryDedupStore) PutIfAbsent(_ context.Context, fingerprint string, record DedupRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.get(fingerprint); ok {
+		return false, nil
+	}
+	s.records[fingerprint] = record
+	return true, nil
+}
+
+// Replace implements DedupStore.
+func (s *MemoryDedupStore) Replace(_ context.Context, fingerprint string, old, record DedupRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.get(fingerprint); !ok || !current.Equal(old) {
+		return false, nil
+	}
+	s.records[fingerprint] = record
+	return true, nil
+}
+
+// Delete implements DedupStore.
+func (s *MemoryDedupStore) Delete(_ context.Context, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, fingerprint)
+	return nil
+}
+
+// Prune removes the records older than the TTL and returns how many were removed.
+func (s *MemoryDedupStore) Prune() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var removed int
+	for fp, record := range s.records {
+		if s.expired(record, now) {
+			delete(s.records, fp)
+			removed++
+		}
+	}
+	return removed
+}
+
+// submitWorkCardDeduplicated skips entries recorded as submitted, reconciles
+// pending entries against ListSubmissions, claims the rest and records the outcome
+// of the claimed entries.
+func (c *Client) submitWorkCardDeduplicated(ctx context.Context, companyWorkCards []CompanyWorkCard) ([]SubmissionResponse, error) {
+	remaining, records, err := c.filterSubmittedWorkCards(ctx, companyWorkCards)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	remaining, err = c.claimWorkCards(ctx, remaining, records, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(remaining) == 0 {
+		return []SubmissionResponse{}, nil
+	}
+
+	chunks, submitErr := c.SubmitWorkCardChunks(ctx, remaining)
+	if chunks == nil && submitErr != nil {
+		c.forgetWorkCards(ctx, remaining)
+		return nil, submitErr
+	}
+
+	var responses []SubmissionResponse
+	var recordErr error
+	for _, chunk := range chunks {
+		responses = append(responses, chunk.Responses...)
+
+		var apiErr *APIError
+		switch {
+		case chunk.Err == nil:
+			for i, cwc := range chunk.CompanyWorkCards {
+				record := DedupRecord{State: DedupSubmitted, Protocol: chunkProtocol(chunk, i), RecordedAt: now}
+				for _, card := range cwc.CardDetails {
+					fp := WorkCardFingerprint(cwc.EmployerTaxID, cwc.BusinessBranchNumber, card)
+					if err := c.dedupStore.Put(ctx, fp, record); err != nil && recordErr == nil {
+						recordErr = fmt.Errorf("%w: %v", ErrNotRecorded, err)
+					}
+				}
+			}
+		case errors.Is(chunk.Err, ErrNotSubmitted), errors.As(chunk.Err, &apiErr) && rejected(apiErr):
+			// The request was not sent or was rejected, so it is safe to resend.
+			c.forgetWorkCards(ctx, chunk.CompanyWorkCards)
+		default:
+			// Other errors, e.g. timeouts, server errors and rate limiting, leave
+			// the entries pending so that the next attempt reconciles them, since
+			// Ergani may have accepted them.
+			c.markWorkCardsPending(ctx, chunk.CompanyWorkCards, now)
+		}
+	}
+	if submitErr != nil {
+		return responses, submitErr
+	}
+	if recordErr != nil {
+		return responses, recordErr
+	}
+	if responses == nil {
+		responses = []SubmissionResponse{}
+	}
+	return responses, nil
+}
+
+// claimWorkCards records the entries as sending and returns the ones it claimed.
+// Entries without a record are claimed with PutIfAbsent and entries with a pending
+// record that Ergani does not have with Replace, so that an entry whose record
+// changed in the meantime, e.g. because a concurrent call claimed it, is dropped
+// instead of being sent twice.
+func (c *Client) claimWorkCards(ctx context.Context, companyWorkCards []CompanyWorkCard, records map[string]DedupRecord, now time.Time) ([]CompanyWorkCard, error) {
+	sending := DedupRecord{State: DedupSending, RecordedAt: now}
+
+	var claimed []CompanyWorkCard
+	for _, cwc := range companyWorkCards {
+		kept := cwc
+		kept.CardDetails = nil
+		for _, card := range cwc.CardDetails {
+			fp := WorkCardFingerprint(cwc.EmployerTaxID, cwc.BusinessBranchNumber, card)
+
+			var ok bool
+			var err error
+			if old, seen := records[fp]; seen {
+				ok, err = c.dedupStore.Replace(ctx, fp, old, sending)
+			} else {
+				ok, err = c.dedupStore.PutIfAbsent(ctx, fp, sending)
+			}
+			if err != nil {
+				c.forgetWorkCards(ctx, append(claimed, kept))
+				return nil, fmt.Errorf("failed to record work card: %w", err)
+			}
+			if ok {
+				kept.CardDetails = append(kept.CardDetails, card)
+			}
+		}
+		if len(kept.CardDetails) > 0 {
+			claimed = append(claimed, kept)
+		}
+	}
+	return claimed, nil
+}
+
+// filterSubmittedWorkCards drops entries that are known to be submitted or that
+// another call is sending. Pending entries are looked up among the work card
+// submissions of their branch since they were sent; entries that are found are
+// recorded as submitted and dropped. The records read are returned along with the
+// remaining entries.
+func (c *Client) filterSubmittedWorkCards(ctx context.Context, companyWorkCards []CompanyWorkCard) ([]CompanyWorkCard, map[string]DedupRecord, error) {
+	type branchKey struct {
+		employerTaxID string
+		branch        int
+	}
+	pendingSince := make(map[branchKey]time.Time)
+	records := make(map[string]DedupRecord)
+	now := c.now()
+
+	for _, cwc := range companyWorkCards {
+		for _, card := range cwc.CardDetails {
+			fp := WorkCardFingerprint(cwc.EmployerTaxID, cwc.BusinessBranchNumber, card)
+			record, ok, err := c.dedupStore.Get(ctx, fp)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to look up work card: %w", err)
+			}
+			if !ok {
+				continue
+			}
+			records[fp] = record
+
+			key := branchKey{cwc.EmployerTaxID, cwc.BusinessBranchNumber}
+			if since, seen := pendingSince[key]; unresolved(record, now) && (!seen || record.RecordedAt.Before(since)) {
+				pendingSince[key] = record.RecordedAt
+			}
+		}
+	}
+
+	for key, since := range pendingSince {
+		branch := key.branch
+		submissions, err := c.ListSubmissions(ctx, SubmissionFilter{
+			From:                 since,
+			To:                   c.now(),
+			DocumentType:         WorkCardDocument,
+			BusinessBranchNumber: &branch,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to reconcile pending work cards: %w", err)
+		}
+
+		for _, sub := range submissions {
+			doc, err := sub.CompanyWorkCard()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to reconcile pending work cards: %w", err)
+			}
+			for _, card := range doc.CardDetails {
+				fp := WorkCardFingerprint(doc.EmployerTaxID, doc.BusinessBranchNumber, card)
+				if record, ok := records[fp]; !ok || !unresolved(record, now) {
+					continue
+				}
+				record := DedupRecord{State: DedupSubmitted, Protocol: sub.Protocol, RecordedAt: c.now()}
+				if err := c.dedupStore.Put(ctx, fp, record); err != nil {
+					return nil, nil, fmt.Errorf("failed to record work card: %w", err)
+				}
+				records[fp] = record
+			}
+		}
+	}
+
+	var remaining []CompanyWorkCard
+	for _, cwc := range companyWorkCards {
+		filtered := cwc
+		filtered.CardDetails = nil
+		for _, card := range cwc.CardDetails {
+			fp := WorkCardFingerprint(cwc.EmployerTaxID, cwc.BusinessBranchNumber, card)
+			if record, ok := records[fp]; !ok || unresolved(record, now) {
+				filtered.CardDetails = append(filtered.CardDetails, card)
+			}
+		}
+		if len(filtered.CardDetails) > 0 {
+			remaining = append(remaining, filtered)
+		}
+	}
+	return remaining, records, nil
+}
+
+// rejected reports whether Ergani refused a request, so that none of its entries
+// were accepted. Server errors, including gateway timeouts, and rate limiting may
+// come back after the entries were accepted.
+func rejected(apiErr *APIError) bool {
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// unresolved reports whether the outcome of a recorded entry is unknown and must
+// be reconciled, i.e. it is pending or its claim has expired.
+func unresolved(record DedupRecord, now time.Time) bool {
+	switch record.State {
+	case DedupPending:
+		return true
+	case DedupSending:
+		return now.Sub(record.RecordedAt) >= DedupClaimTimeout
+	default:
+		return false
+	}
+}
+
+// markWorkCardsPending records claimed entries whose outcome is unknown as
+// pending. Errors are ignored since the claim expires after DedupClaimTimeout.
+func (c *Client) markWorkCardsPending(ctx context.Context, companyWorkCards []CompanyWorkCard, sentAt time.Time) {
+	for _, cwc := range companyWorkCards {
+		for _, card := range cwc.CardDetails {
+			fp := WorkCardFingerprint(cwc.EmployerTaxID, cwc.BusinessBranchNumber, card)
+			_ = c.dedupStore.Put(ctx, fp, DedupRecord{State: DedupPending, RecordedAt: sentAt})
+		}
+	}
+}
+
+// forgetWorkCards removes the records of entries that were not accepted. Errors
+// are ignored since a stale pending record only causes a lookup on the next attempt.
+func (c *Client) forgetWorkCards(ctx context.Context, companyWorkCards []CompanyWorkCard) {
+	for _, cwc := range companyWorkCards {
+		for _, card := range cwc.CardDetails {
+			_ = c.dedupStore.Delete(ctx, WorkCardFingerprint(cwc.EmployerTaxID, cwc.BusinessBranchNumber, card))
+		}
+	}
+}
+
+// chunkProtocol returns the protocol covering the i-th document of a chunk.
+func chunkProtocol(chunk WorkCardChunk, i int) string {
+	switch {
+	case len(chunk.Responses) == len(chunk.CompanyWorkCards):
+		return chunk.Responses[i].Protocol
+	case len(chunk.Responses) > 0:
+		return chunk.Responses[0].Protocol
+	default:
+		return ""
+	}
+}
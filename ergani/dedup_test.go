@@ -0,0 +1,251 @@
+package ergani
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkCardFingerprint(t *testing.T) {
+	at := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	card := WorkCard{EmployeeTaxID: "123456789", WorkCardMovementType: Arrival, WorkCardMovementDateTime: DateTime{Time: at}}
+
+	local := card
+	local.WorkCardMovementDateTime = DateTime{Time: at.In(time.FixedZone("EEST", 3*60*60))}
+	local.EmployeeLastName = "Doe"
+	if WorkCardFingerprint("999999999", 1, card) != WorkCardFingerprint("999999999", 1, local) {
+		t.Error("Expected the fingerprint to ignore names and time zones")
+	}
+
+	departure := card
+	departure.WorkCardMovementType = Departure
+	if WorkCardFingerprint("999999999", 1, card) == WorkCardFingerprint("999999999", 1, departure) {
+		t.Error("Expected different movement types to have different fingerprints")
+	}
+	if WorkCardFingerprint("999999999", 1, card) == WorkCardFingerprint("999999999", 2, card) {
+		t.Error("Expected different branches to have different fingerprints")
+	}
+}
+
+func TestSubmitWorkCardDedup(t *testing.T) {
+	var sent []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Authentication", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accessToken": "test-token"}`))
+	})
+	mux.HandleFunc("/Documents/WRKCardSE", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = append(sent, string(body))
+		w.Write([]byte(`[{"id": "sub1", "protocol": "proto1", "submitDate": "10/07/2025 14:56"}]`))
+	})
+	mux.HandleFunc("/Documents/Submissions", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "WRKCardSE" || r.URL.Query().Get("branch") != "0" {
+			t.Errorf("Unexpected submission filter: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"id": "sub0", "protocol": "proto0", "submitDate": "10/07/2025 09:01", "type": "WRKCardSE", "branch": 0,
+			"document": {"f_afm_ergodoti": "999999999", "f_aa": 0, "Details>CardDetails": [
+				{"f_afm": "000000000", "f_eponymo": "Doe", "f_onoma": "John", "f_type": "0", "f_reference_date": "10/07/2025", "f_date": "2025-07-10T09:00:00Z"}
+			]}}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := NewMemoryDedupStore(0)
+	client, _ := NewClientWithConfig(Config{BaseURL: server.URL, DedupStore: store})
+	ctx := context.Background()
+	cards := testCompanyWorkCards(1, 2)
+
+	responses, err := client.SubmitWorkCard(ctx, cards)
+	if err != nil || len(responses) != 1 || len(sent) != 1 {
+		t.Fatalf("Expected a single request, got %d requests, %v, %v", len(sent), responses, err)
+	}
+	fp := WorkCardFingerprint("999999999", 0, cards[0].CardDetails[1])
+	if record, ok, _ := store.Get(ctx, fp); !ok || record.State != DedupSubmitted || record.Protocol != "proto1" {
+		t.Errorf("Expected the entry to be recorded as submitted, got %+v", record)
+	}
+
+	t.Run("SkipSubmitted", func(t *testing.T) {
+		sent = nil
+		responses, err := client.SubmitWorkCard(ctx, cards)
+		if err != nil || len(responses) != 0 || len(sent) != 0 {
+			t.Errorf("Expected no request for submitted entries, got %d requests, %v, %v", len(sent), responses, err)
+		}
+	})
+
+	t.Run("ReconcilePending", func(t *testing.T) {
+		sent = nil
+		for _, card := range cards[0].CardDetails {
+			store.Put(ctx, WorkCardFingerprint("999999999", 0, card), DedupRecord{State: DedupPending, RecordedAt: time.Now()})
+		}
+
+		if _, err := client.SubmitWorkCard(ctx, cards); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(sent) != 1 || strings.Contains(sent[0], `"000000000"`) || !strings.Contains(sent[0], `"000000001"`) {
+			t.Errorf("Expected only the entry missing from Ergani to be resent, got %v", sent)
+		}
+		record, _, _ := store.Get(ctx, WorkCardFingerprint("999999999", 0, cards[0].CardDetails[0]))
+		if record.State != DedupSubmitted || record.Protocol != "proto0" {
+			t.Errorf("Expected the found entry to be reconciled with its protocol, got %+v", record)
+		}
+	})
+}
+
+func TestSubmitWorkCardDedup_Concurrent(t *testing.T) {
+	var mu sync.Mutex
+	var entries int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Authentication", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accessToken": "test-token"}`))
+	})
+	mux.HandleFunc("/Documents/WRKCardSE", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		entries += strings.Count(string(body), `"f_afm"`)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`[{"id": "sub1", "protocol": "proto1", "submitDate": "10/07/2025 14:56"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{BaseURL: server.URL, DedupStore: NewMemoryDedupStore(0)})
+	cards := testCompanyWorkCards(1, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.SubmitWorkCard(context.Background(), cards); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entries != 2 {
+		t.Errorf("Expected each entry to be sent once, got %d entries sent", entries)
+	}
+}
+
+// unrecordedDedupStore fails to record entries as submitted.
+type unrecordedDedupStore struct {
+	*MemoryDedupStore
+}
+
+func (s unrecordedDedupStore) Put(ctx context.Context, fingerprint string, record DedupRecord) error {
+	if record.State == DedupSubmitted {
+		return errors.New("disk full")
+	}
+	return s.MemoryDedupStore.Put(ctx, fingerprint, record)
+}
+
+func TestSubmitWorkCardDedup_NotRecorded(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Authentication", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accessToken": "test-token"}`))
+	})
+	mux.HandleFunc("/Documents/WRKCardSE", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": "sub1", "protocol": "proto1", "submitDate": "10/07/2025 14:56"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{BaseURL: server.URL, DedupStore: unrecordedDedupStore{NewMemoryDedupStore(0)}})
+
+	responses, err := client.SubmitWorkCard(context.Background(), testCompanyWorkCards(1, 2))
+	if !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected ErrNotRecorded, got %v", err)
+	}
+	if len(responses) != 1 || responses[0].Protocol != "proto1" {
+		t.Errorf("Expected the responses of the accepted request, got %+v", responses)
+	}
+}
+
+func TestMemoryDedupStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryDedupStore(24 * time.Hour)
+	store.now = func() time.Time { return now }
+
+	store.Put(ctx, "old", DedupRecord{State: DedupSubmitted, RecordedAt: now.Add(-25 * time.Hour)})
+	store.Put(ctx, "new", DedupRecord{State: DedupSubmitted, RecordedAt: now.Add(-time.Hour)})
+
+	if _, ok, _ := store.Get(ctx, "old"); ok {
+		t.Error("Expected the expired record to be absent")
+	}
+	if ok, _ := store.PutIfAbsent(ctx, "old", DedupRecord{State: DedupSending, RecordedAt: now}); !ok {
+		t.Error("Expected an expired record to be replaced by PutIfAbsent")
+	}
+
+	now = now.Add(23 * time.Hour)
+	if removed := store.Prune(); removed != 1 {
+		t.Errorf("Expected 1 record to be pruned, got %d", removed)
+	}
+	if _, ok, _ := store.Get(ctx, "new"); ok {
+		t.Error("Expected the pruned record to be gone")
+	}
+	if _, ok, _ := store.Get(ctx, "old"); !ok {
+		t.Error("Expected the recent record to be kept")
+	}
+}
+
+func TestSubmitWorkCardDedup_GatewayTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	accepted := make(map[string]string) // branch -> request body
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Authentication", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"accessToken": "test-token"}`))
+	})
+	mux.HandleFunc("/Documents/WRKCardSE", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, string(body))
+		// Ergani accepts both branches, but the answer for branch 1 is lost.
+		if strings.Contains(string(body), `"f_aa":1`) {
+			accepted["1"] = string(body)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"id": "sub1", "protocol": "proto1", "submitDate": "10/07/2025 14:56"}]`))
+	})
+	mux.HandleFunc("/Documents/Submissions", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("branch") != "1" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"id": "sub2", "protocol": "proto2", "submitDate": "10/07/2025 09:01", "type": "WRKCardSE", "branch": 1,
+			"document": {"f_afm_ergodoti": "999999999", "f_aa": 1, "Details>CardDetails": [
+				{"f_afm": "000001000", "f_eponymo": "Doe", "f_onoma": "John", "f_type": "0", "f_reference_date": "10/07/2025", "f_date": "2025-07-10T09:00:00Z"}
+			]}}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewClientWithConfig(Config{BaseURL: server.URL, DedupStore: NewMemoryDedupStore(0), MaxCardsPerRequest: 1})
+	ctx := context.Background()
+	cards := testCompanyWorkCards(2, 1)
+
+	var apiErr *APIError
+	if _, err := client.SubmitWorkCard(ctx, cards); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected the gateway error, got %v", err)
+	}
+	if accepted["1"] == "" || len(sent) != 2 {
+		t.Fatalf("Expected both branches to be sent, got %v", sent)
+	}
+
+	sent = nil
+	responses, err := client.SubmitWorkCard(ctx, cards)
+	if err != nil || len(responses) != 0 || len(sent) != 0 {
+		t.Errorf("Expected the accepted entries not to be resent, got %d requests, %v, %v", len(sent), responses, err)
+	}
+}
@@ -0,0 +1,108 @@
+package ergani
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// GetEmployer retrieves the authenticated employer together with its branches.
+func (c *Client) GetEmployer(ctx context.Context) (*Employer, error) {
+	resp, err := c.request(ctx, http.MethodGet, "/Employer", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var employer Employer
+	decodeErr := json.NewDecoder(resp.Body).Decode(&employer)
+	closeErr := resp.Body.Close()
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode employer: %w", decodeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return &employer, nil
+}
+
+// ListBranches retrieves the branches of the authenticated employer with their
+// numbers, addresses, activity, Kallikratis and SEPE service codes.
+func (c *Client) ListBranches(ctx context.Context) ([]Branch, error) {
+	resp, err := c.request(ctx, http.MethodGet, "/Employer/Branches", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return []Branch{}, resp.Body.Close()
+	}
+
+	var branches []Branch
+	decodeErr := json.NewDecoder(resp.Body).Decode(&branches)
+	closeErr := resp.Body.Close()
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode branches: %w", decodeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return branches, nil
+}
+
+// Branch returns the employer's branch with the given number.
+func (e Employer) Branch(businessBranchNumber int) (Branch, bool) {
+	for _, b := range e.Branches {
+		if b.BusinessBranchNumber == businessBranchNumber {
+			return b, true
+		}
+	}
+	return Branch{}, false
+}
+
+// OvertimeHeader returns a CompanyOvertime for the branch with its header fields
+// pre-filled from the employer and branch data. Only the employee rows are left
+// for the caller.
+func (e Employer) OvertimeHeader(b Branch) CompanyOvertime {
+	o := CompanyOvertime{
+		BusinessBranchNumber: b.BusinessBranchNumber,
+		SEPEServiceCode:      b.SEPEServiceCode,
+		PrimaryActivityCode:  e.PrimaryActivityCode,
+		BranchActivityCode:   b.ActivityCode,
+		KallikratisCode:      b.KallikratisCode,
+		LegalRepTaxID:        e.LegalRepTaxID,
+		EmployerOrganization: e.EmployerOrganization,
+	}
+
+	secondary := []*string{&o.SecondaryActivityCode1, &o.SecondaryActivityCode2, &o.SecondaryActivityCode3, &o.SecondaryActivityCode4}
+	for i, code := range e.SecondaryActivityCodes {
+		if i == len(secondary) {
+			break
+		}
+		*secondary[i] = code
+	}
+
+	return o
+}
+
+// WorkCardHeader returns a CompanyWorkCard for the branch with the employer tax ID
+// and branch number pre-filled.
+func (e Employer) WorkCardHeader(b Branch) CompanyWorkCard {
+	return CompanyWorkCard{
+		EmployerTaxID:        e.EmployerTaxID,
+		BusinessBranchNumber: b.BusinessBranchNumber,
+	}
+}
+
+// DailyScheduleHeader returns a CompanyDailySchedule for the branch.
+func (b Branch) DailyScheduleHeader() CompanyDailySchedule {
+	return CompanyDailySchedule{BusinessBranchNumber: b.BusinessBranchNumber}
+}
+
+// WeeklyScheduleHeader returns a CompanyWeeklySchedule for the branch.
+func (b Branch) WeeklyScheduleHeader() CompanyWeeklySchedule {
+	return CompanyWeeklySchedule{BusinessBranchNumber: b.BusinessBranchNumber}
+}
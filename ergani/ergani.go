@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
@@ -18,6 +19,9 @@ const (
 	defaultBaseURL   = "https://trialeservices.yeka.gr/WebServicesAPI/api"
 	UserTypeEmployer = "01"
 	DefaultTimeout   = 30 * time.Second
+	// DefaultCorrectionWindow is the default period after a work card movement
+	// within which it can still be corrected or cancelled.
+	DefaultCorrectionWindow = 24 * time.Hour
 )
 
 type HTTPClient interface {
@@ -30,6 +34,28 @@ type Config struct {
 	BaseURL    string
 	Timeout    time.Duration
 	HTTPClient HTTPClient
+	// CorrectionWindow limits how long after a movement a work card entry can be
+	// corrected or cancelled. Defaults to DefaultCorrectionWindow.
+	CorrectionWindow time.Duration
+	// MaxCardsPerRequest splits SubmitWorkCard into requests of at most this many
+	// work card entries. Zero disables splitting by count.
+	MaxCardsPerRequest int
+	// MaxRequestBytes splits SubmitWorkCard so that no encoded request body exceeds
+	// this size. Zero disables splitting by size.
+	MaxRequestBytes int
+	// MaxConcurrentRequests bounds how many chunks of a split submission are sent in
+	// parallel. Defaults to 1, i.e. chunks are sent sequentially.
+	MaxConcurrentRequests int
+	// LateDeclarationPolicy, if set, justifies work card entries submitted after the
+	// real-time window.
+	LateDeclarationPolicy *LateDeclarationPolicy
+	// DedupStore, if set, makes SubmitWorkCard skip entries that were already
+	// accepted, so that retries after a timeout do not create duplicates.
+	DedupStore DedupStore
+	// FullTimeWeeklyHours is the weekly working time from which an employment
+	// change counts as full-time, e.g. 37.5 in sectors with a shorter week.
+	// Defaults to FullTimeWeeklyHours.
+	FullTimeWeeklyHours float64
 }
 
 // Client is a client for interacting with the Ergani API.
@@ -37,9 +63,22 @@ type Config struct {
 type Client struct {
 	baseURL    *url.URL
 	httpClient HTTPClient
-	token      string
 	username   string
 	password   string
+
+	// mu guards token, which is shared by concurrent chunk submissions.
+	mu    sync.Mutex
+	token string
+
+	correctionWindow      time.Duration
+	maxCardsPerRequest    int
+	maxRequestBytes       int
+	maxConcurrentRequests int
+	lateDeclarationPolicy *LateDeclarationPolicy
+	dedupStore            DedupStore
+	fullTimeWeeklyHours   float64
+	// now returns the current time and is replaced in tests.
+	now func() time.Time
 }
 
 func NewClientWithConfig(config Config) (*Client, error) {
@@ -57,11 +96,34 @@ func NewClientWithConfig(config Config) (*Client, error) {
 		httpClient = &http.Client{Timeout: timeout}
 	}
 
+	correctionWindow := config.CorrectionWindow
+	if correctionWindow == 0 {
+		correctionWindow = DefaultCorrectionWindow
+	}
+
+	maxConcurrentRequests := config.MaxConcurrentRequests
+	if maxConcurrentRequests < 1 {
+		maxConcurrentRequests = 1
+	}
+
+	fullTimeWeeklyHours := config.FullTimeWeeklyHours
+	if fullTimeWeeklyHours <= 0 {
+		fullTimeWeeklyHours = FullTimeWeeklyHours
+	}
+
 	c := &Client{
-		baseURL:    baseURL,
-		httpClient: httpClient,
-		username:   config.Username,
-		password:   config.Password,
+		baseURL:               baseURL,
+		httpClient:            httpClient,
+		username:              config.Username,
+		password:              config.Password,
+		correctionWindow:      correctionWindow,
+		maxCardsPerRequest:    config.MaxCardsPerRequest,
+		maxRequestBytes:       config.MaxRequestBytes,
+		maxConcurrentRequests: maxConcurrentRequests,
+		lateDeclarationPolicy: config.LateDeclarationPolicy,
+		dedupStore:            config.DedupStore,
+		fullTimeWeeklyHours:   fullTimeWeeklyHours,
+		now:                   time.Now,
 	}
 
 	return c, nil
@@ -143,36 +205,59 @@ func (c *Client) authenticate(ctx context.Context, username, password string) er
 	return nil
 }
 
+// accessToken returns the current access token, authenticating first if the client
+// has none yet.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == "" {
+		if err := c.authenticate(ctx, c.username, c.password); err != nil {
+			return "", err
+		}
+	}
+	return c.token, nil
+}
+
 // request is a helper function to create, execute, and handle a generic API request.
 // It marshals the payload, sets necessary headers (including the auth token),
 // and handles non-successful status codes.
 func (c *Client) request(ctx context.Context, method, path string, payload interface{}) (*http.Response, error) {
-	if c.token == "" {
-		if err := c.authenticate(ctx, c.username, c.password); err != nil {
-			return nil, err
-		}
+	return c.requestURL(ctx, method, c.baseURL.JoinPath(path), payload)
+}
+
+// requestURL performs an API request against a fully built endpoint, e.g. one that
+// carries query parameters. See request for details. A request rejected with 401
+// Unauthorized, e.g. because the token expired, is sent once more with a new token.
+func (c *Client) requestURL(ctx context.Context, method string, endpoint *url.URL, payload interface{}) (*http.Response, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
 	}
-	var body io.Reader
+
+	var bodyBytes []byte
 	if payload != nil {
-		bodyBytes, err := json.Marshal(payload)
+		bodyBytes, err = json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request payload: %w", err)
 		}
-		body = bytes.NewReader(bodyBytes)
 	}
 
-	endpoint := c.baseURL.JoinPath(path)
-	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	resp, err := c.send(ctx, method, endpoint, bodyBytes, token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.token)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request to %s failed: %w", path, err)
+	if resp.StatusCode == http.StatusUnauthorized {
+		if err := resp.Body.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close response body: %w", err)
+		}
+		c.invalidateToken(token)
+		if token, err = c.accessToken(ctx); err != nil {
+			return nil, err
+		}
+		if resp, err = c.send(ctx, method, endpoint, bodyBytes, token); err != nil {
+			return nil, err
+		}
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -186,10 +271,80 @@ func (c *Client) request(ctx context.Context, method, path string, payload inter
 	return resp, nil
 }
 
+// send performs a single authorized request.
+func (c *Client) send(ctx context.Context, method string, endpoint *url.URL, bodyBytes []byte, token string) (*http.Response, error) {
+	var body io.Reader
+	if bodyBytes != nil {
+		body = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", endpoint.Path, err)
+	}
+	return resp, nil
+}
+
+// invalidateToken forgets a rejected token so that the next request authenticates
+// again. A token that was already replaced by a concurrent request is kept.
+func (c *Client) invalidateToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == token {
+		c.token = ""
+	}
+}
+
 // SubmitWorkCard submits work card records (check-in/check-out) for employees.
 // It takes a slice of CompanyWorkCard, each representing the records for a specific
 // business branch.
+//
+// When MaxCardsPerRequest or MaxRequestBytes is configured, the submission is split
+// into chunks (see SubmitWorkCardChunks) and the responses of all chunks are merged
+// in input order. If a chunk fails, the responses of the chunks that were accepted
+// are returned together with the error.
+//
+// Late entries are justified according to the configured LateDeclarationPolicy or
+// WithLateJustification before they are sent.
+//
+// When a DedupStore is configured, entries recorded as submitted or being sent by
+// a concurrent call are skipped and entries whose earlier outcome is unknown are
+// first looked up with ListSubmissions. The responses only cover the entries that
+// were actually sent. If the accepted entries cannot be recorded as submitted, the
+// responses are returned with an error wrapping ErrNotRecorded.
 func (c *Client) SubmitWorkCard(ctx context.Context, companyWorkCards []CompanyWorkCard) ([]SubmissionResponse, error) {
+	if c.dedupStore != nil {
+		return c.submitWorkCardDeduplicated(ctx, companyWorkCards)
+	}
+	if c.maxCardsPerRequest == 0 && c.maxRequestBytes == 0 {
+		return c.submitWorkCardRequest(ctx, c.applyLateDeclarationPolicy(ctx, companyWorkCards))
+	}
+
+	chunks, err := c.SubmitWorkCardChunks(ctx, companyWorkCards)
+	var responses []SubmissionResponse
+	for _, chunk := range chunks {
+		responses = append(responses, chunk.Responses...)
+	}
+	if err != nil {
+		return responses, err
+	}
+	if responses == nil {
+		responses = []SubmissionResponse{}
+	}
+	return responses, nil
+}
+
+// submitWorkCardRequest submits work card records in a single request.
+func (c *Client) submitWorkCardRequest(ctx context.Context, companyWorkCards []CompanyWorkCard) ([]SubmissionResponse, error) {
 	// The API expects the payload to be nested within "Cards" and "Card" keys.
 	payload := map[string]map[string][]CompanyWorkCard{
 		"Cards": {"Card": companyWorkCards},
@@ -238,6 +393,18 @@ func (c *Client) SubmitOvertime(ctx context.Context, companyOvertimes []CompanyO
 	return parsed, nil
 }
 
+// CancelOvertime cancels overtime that was submitted with SubmitOvertime. The
+// submitted CompanyOvertime must be the document that produced the original
+// response; when rows are given, only those employee rows are cancelled.
+// See NewOvertimeCancellation for how the cancellation document is built.
+func (c *Client) CancelOvertime(ctx context.Context, original SubmissionResponse, submitted CompanyOvertime, rows ...Overtime) ([]SubmissionResponse, error) {
+	cancellation, err := NewOvertimeCancellation(original, submitted, rows...)
+	if err != nil {
+		return nil, err
+	}
+	return c.SubmitOvertime(ctx, []CompanyOvertime{cancellation})
+}
+
 // SubmitDailySchedule submits daily work schedules for employees.
 // It takes a slice of CompanyDailySchedule, each representing the schedules for
 // a specific business branch.
@@ -264,6 +431,19 @@ func (c *Client) SubmitDailySchedule(ctx context.Context, companyDailySchedules
 	return parsed, nil
 }
 
+// AmendDailySchedule amends a daily schedule that was already submitted. Given the
+// original response, the previously submitted schedule and the desired state, it
+// submits only the employee days that changed. Days that have already started
+// cannot be amended and are rejected with a *ValidationError; ErrNoScheduleChanges
+// is returned when there is nothing to amend.
+func (c *Client) AmendDailySchedule(ctx context.Context, original SubmissionResponse, previous, desired CompanyDailySchedule) ([]SubmissionResponse, error) {
+	amendment, err := NewDailyScheduleAmendment(original, previous, desired, c.now())
+	if err != nil {
+		return nil, err
+	}
+	return c.SubmitDailySchedule(ctx, []CompanyDailySchedule{amendment})
+}
+
 // SubmitWeeklySchedule submits weekly work schedules for employees.
 // It takes a slice of CompanyWeeklySchedule, each representing the schedules for
 // a specific business branch.
@@ -289,3 +469,168 @@ func (c *Client) SubmitWeeklySchedule(ctx context.Context, companyWeeklySchedule
 
 	return parsed, nil
 }
+
+// SubmitEmploymentChange submits changes to employees' working terms (e.g., a
+// conversion from full-time to part-time or new working hours). Every change is
+// validated before submission and a *ValidationError is returned for the first
+// inconsistent entry.
+func (c *Client) SubmitEmploymentChange(ctx context.Context, companyChanges []CompanyEmploymentChange) ([]SubmissionResponse, error) {
+	for _, cc := range companyChanges {
+		for _, change := range cc.EmployeeChanges {
+			if err := change.validate(c.fullTimeWeeklyHours); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The API expects the payload to be nested within "E9s" and "E9" keys.
+	payload := map[string]map[string][]CompanyEmploymentChange{
+		"E9s": {"E9": companyChanges},
+	}
+	resp, err := c.request(ctx, http.MethodPost, "/Documents/E9", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, parseErr := parseSubmissionResponse(resp)
+	closeErr := resp.Body.Close()
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return parsed, nil
+}
+
+// SubmitAnnualStaffTable submits annual staff tables (E4) with every employee's
+// working hours, breaks and pay. Tables with RelatedProtocolID and
+// RelatedProtocolDate set are submitted as supplementary (amending) tables.
+func (c *Client) SubmitAnnualStaffTable(ctx context.Context, tables []CompanyAnnualStaffTable) ([]SubmissionResponse, error) {
+	for _, t := range tables {
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The API expects the payload to be nested within "E4s" and "E4" keys.
+	payload := map[string]map[string][]CompanyAnnualStaffTable{
+		"E4s": {"E4": tables},
+	}
+	resp, err := c.request(ctx, http.MethodPost, "/Documents/E4", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, parseErr := parseSubmissionResponse(resp)
+	closeErr := resp.Body.Close()
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return parsed, nil
+}
+
+// CorrectWorkCard submits corrections and cancellations of work card entries that
+// were already submitted with SubmitWorkCard. Each CompanyWorkCardCorrection must
+// reference the protocol of the original submission. Entries outside the allowed
+// correction window are rejected with a *ValidationError before anything is sent.
+func (c *Client) CorrectWorkCard(ctx context.Context, companyCorrections []CompanyWorkCardCorrection) ([]SubmissionResponse, error) {
+	now := c.now()
+	for _, cc := range companyCorrections {
+		if err := cc.validate(now, c.correctionWindow); err != nil {
+			return nil, err
+		}
+	}
+
+	// The API expects the payload to be nested within "Corrections" and "Correction" keys.
+	payload := map[string]map[string][]CompanyWorkCardCorrection{
+		"Corrections": {"Correction": companyCorrections},
+	}
+	resp, err := c.request(ctx, http.MethodPost, "/Documents/WRKCardCorr", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, parseErr := parseSubmissionResponse(resp)
+	closeErr := resp.Body.Close()
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return parsed, nil
+}
+
+// AmendWeeklySchedule amends a weekly schedule that was already submitted. Given
+// the original response, the previously submitted schedule and the desired state,
+// it submits only the employee weekdays that changed. Weekdays with an occurrence
+// that has already started within the desired date range are rejected with a
+// *ValidationError; ErrNoScheduleChanges is returned when there is nothing to amend.
+func (c *Client) AmendWeeklySchedule(ctx context.Context, original SubmissionResponse, previous, desired CompanyWeeklySchedule) ([]SubmissionResponse, error) {
+	amendment, err := NewWeeklyScheduleAmendment(original, previous, desired, c.now())
+	if err != nil {
+		return nil, err
+	}
+	return c.SubmitWeeklySchedule(ctx, []CompanyWeeklySchedule{amendment})
+}
+
+// GetSubmission retrieves a previously submitted document by its protocol number.
+// The returned Submission can be decoded into the matching model with one of its
+// accessors (e.g., Submission.CompanyWorkCard).
+func (c *Client) GetSubmission(ctx context.Context, protocol string) (*Submission, error) {
+	endpoint := c.baseURL.JoinPath("/Documents/Submissions", url.PathEscape(protocol))
+	resp, err := c.requestURL(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var submission Submission
+	decodeErr := json.NewDecoder(resp.Body).Decode(&submission)
+	closeErr := resp.Body.Close()
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode submission: %w", decodeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return &submission, nil
+}
+
+// ListSubmissions retrieves the documents already accepted by the API that match
+// the filter, e.g. to find out what was submitted before a crash.
+func (c *Client) ListSubmissions(ctx context.Context, filter SubmissionFilter) ([]Submission, error) {
+	endpoint := c.baseURL.JoinPath("/Documents/Submissions")
+	endpoint.RawQuery = filter.values().Encode()
+	resp, err := c.requestURL(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return []Submission{}, resp.Body.Close()
+	}
+
+	var submissions []Submission
+	decodeErr := json.NewDecoder(resp.Body).Decode(&submissions)
+	closeErr := resp.Body.Close()
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode submissions: %w", decodeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return submissions, nil
+}
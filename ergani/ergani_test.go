@@ -3,6 +3,7 @@ package ergani
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -85,6 +86,107 @@ func setupTestServer(t *testing.T) *httptest.Server {
 		}
 	})
 
+	mux.HandleFunc("/Documents/E9", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"id": "sub333", "protocol": "proto444", "submitDate": "14/07/2025 09:00"}]`)); err != nil {
+			t.Fatalf("Failed to write response for E9: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Documents/E4", func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Failed to read request body in mock server: %v", err)
+		}
+		if !strings.Contains(string(bodyBytes), `"E4s":{"E4":[`) {
+			t.Errorf("Expected E4 payload envelope, got %s", string(bodyBytes))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"id": "sub555", "protocol": "proto666", "submitDate": "15/07/2025 09:00"}]`)); err != nil {
+			t.Fatalf("Failed to write response for E4: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Documents/WRKCardCorr", func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("Failed to read request body in mock server: %v", err)
+		}
+		if !strings.Contains(string(bodyBytes), `"f_rel_protocol":"proto456"`) {
+			t.Errorf("Expected correction to reference protocol proto456, got %s", string(bodyBytes))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"id": "sub777", "protocol": "proto888", "submitDate": "10/07/2025 15:10"}]`)); err != nil {
+			t.Fatalf("Failed to write response for WRKCardCorr: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Documents/Submissions/proto456", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET for submission lookup, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`{"id": "sub123", "protocol": "proto456", "submitDate": "10/07/2025 14:56", "type": "WRKCardSE", "branch": 1,
+			"document": {"f_afm_ergodoti": "999999999", "f_aa": 1, "Details>CardDetails": [
+				{"f_afm": "123456789", "f_eponymo": "Doe", "f_onoma": "John", "f_type": "0", "f_reference_date": "10/07/2025", "f_date": "2025-07-10T09:00:00Z", "f_aitiologia": "003"}
+			]}}`)); err != nil {
+			t.Fatalf("Failed to write response for submission lookup: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Documents/Submissions", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("fromDate") != "01/07/2025" || q.Get("type") != "OvTime" || q.Get("branch") != "0" {
+			t.Errorf("Unexpected submission filter: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"id": "sub456", "protocol": "proto789", "submitDate": "11/07/2025 10:00", "type": "OvTime", "branch": 0,
+			"document": {"f_aa_pararthmatos": 0, "Ergazomenoi>OvertimeErgazomenosDate": [
+				{"f_afm": "123456789", "f_date": "11/07/2025", "f_from": "17:00", "f_to": "19:00", "f_cancellation": "0", "f_reason": "003", "f_weekdates": 5}
+			]}}]`)); err != nil {
+			t.Fatalf("Failed to write response for submission list: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Documents/Submissions/proto456/Receipt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("%PDF-1.4 receipt")); err != nil {
+			t.Fatalf("Failed to write response for receipt: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Lookups/KAD", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"code": "5610", "description": "ΕΣΤΙΑΤΟΡΙΑ"}]`)); err != nil {
+			t.Fatalf("Failed to write response for KAD lookup: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Employer", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`{"f_afm_ergodoti": "999999999", "f_eponymia": "ACME", "f_afm_proswpoy": "111111111", "f_kad_kyria": "5610", "f_kad_deyt": ["5630"],
+			"Parartimata>Parartima": [{"f_aa_pararthmatos": 0, "f_kad_pararthmatos": "5610", "f_kallikratis_pararthmatos": "9186", "f_ypiresia_sepe": "10000"}]}`)); err != nil {
+			t.Fatalf("Failed to write response for Employer: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Employer/Branches", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"f_aa_pararthmatos": 0, "f_dieythynsi": "ΠΑΝΕΠΙΣΤΗΜΙΟΥ 1"}, {"f_aa_pararthmatos": 1, "f_dieythynsi": "ΕΡΜΟΥ 2"}]`)); err != nil {
+			t.Fatalf("Failed to write response for Branches: %v", err)
+		}
+	})
+
+	mux.HandleFunc("/Employer/Branches/1/Employees", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte(`[{"f_afm": "123456789", "f_amka": "01017000000", "f_eponymo": "ΠΑΠΑΔΟΠΟΥΛΟΣ", "f_onoma": "ΓΙΩΡΓΟΣ", "f_aa_pararthmatos": 1}]`)); err != nil {
+			t.Fatalf("Failed to write response for Employees: %v", err)
+		}
+	})
+
 	return httptest.NewServer(mux)
 }
 
@@ -168,6 +270,42 @@ func TestSubmitWorkCard_Success(t *testing.T) {
 	}
 }
 
+func TestSubmitWorkCard_ExpiredToken(t *testing.T) {
+	var logins int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Authentication", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		if _, err := fmt.Fprintf(w, `{"accessToken": "token-%d"}`, logins+1); err != nil {
+			t.Fatalf("Failed to write auth response: %v", err)
+		}
+	})
+	mux.HandleFunc("/Documents/WRKCardSE", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil || len(body) == 0 {
+			t.Errorf("Expected the request body to be resent, got %q, %v", body, err)
+		}
+		if _, err := w.Write([]byte(`[{"id": "1", "protocol": "P1", "submitDate": "01/01/2025 09:00"}]`)); err != nil {
+			t.Fatalf("Failed to write response: %v", err)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+	client.token = "token-1"
+
+	if _, err := client.SubmitWorkCard(context.Background(), []CompanyWorkCard{}); err != nil {
+		t.Fatalf("Expected the request to succeed with a new token, got %v", err)
+	}
+	if logins != 1 || client.token != "token-2" {
+		t.Errorf("Expected one re-authentication, got %d logins and token %q", logins, client.token)
+	}
+}
+
 func TestSubmitWorkCard_APIError(t *testing.T) {
 	server := setupTestServer(t)
 	defer server.Close()
@@ -288,3 +426,363 @@ func TestSubmitWeeklySchedule_Success(t *testing.T) {
 		t.Errorf("Expected submission ID '%s', got '%s'", expectedID, responses[0].ID)
 	}
 }
+
+func TestSubmitEmploymentChange_Success(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	changes := []CompanyEmploymentChange{
+		{
+			BusinessBranchNumber: 1,
+			EmployeeChanges: []EmploymentChange{
+				{
+					EmployeeTaxID: "123456789",
+					ChangeType:    FullTimeToPartTime,
+					ChangeDate:    Date{Time: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC)},
+					ContractType:  PartTime,
+					WeeklyHours:   20,
+				},
+			},
+		},
+	}
+
+	responses, err := client.SubmitEmploymentChange(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("Expected no error on SubmitEmploymentChange, but got: %v", err)
+	}
+
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 submission response, got %d", len(responses))
+	}
+
+	expectedID := "sub333"
+	if responses[0].ID != expectedID {
+		t.Errorf("Expected submission ID '%s', got '%s'", expectedID, responses[0].ID)
+	}
+}
+
+func TestSubmitEmploymentChange_ValidationError(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	changes := []CompanyEmploymentChange{
+		{
+			BusinessBranchNumber: 1,
+			EmployeeChanges: []EmploymentChange{
+				{
+					EmployeeTaxID: "123456789",
+					ChangeType:    FullTimeToPartTime,
+					ContractType:  PartTime,
+					WeeklyHours:   40,
+				},
+			},
+		},
+	}
+
+	_, err := client.SubmitEmploymentChange(context.Background(), changes)
+	if err == nil {
+		t.Fatal("Expected a ValidationError, but got nil")
+	}
+	if _, ok := err.(*ValidationError); !ok {
+		t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+	}
+}
+
+func TestSubmitAnnualStaffTable_Success(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	tables := []CompanyAnnualStaffTable{
+		{
+			BusinessBranchNumber: 1,
+			Year:                 2025,
+			Employees: []StaffTableEmployee{
+				{
+					EmployeeTaxID: "123456789",
+					ContractType:  FullTime,
+					WeeklyHours:   40,
+					GrossPay:      1200,
+				},
+			},
+		},
+	}
+
+	responses, err := client.SubmitAnnualStaffTable(context.Background(), tables)
+	if err != nil {
+		t.Fatalf("Expected no error on SubmitAnnualStaffTable, but got: %v", err)
+	}
+
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 submission response, got %d", len(responses))
+	}
+
+	expectedID := "sub555"
+	if responses[0].ID != expectedID {
+		t.Errorf("Expected submission ID '%s', got '%s'", expectedID, responses[0].ID)
+	}
+}
+
+func TestSubmitAnnualStaffTable_IncompleteAmendment(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	tables := []CompanyAnnualStaffTable{
+		{BusinessBranchNumber: 1, Year: 2025, RelatedProtocolID: "proto666"},
+	}
+
+	_, err := client.SubmitAnnualStaffTable(context.Background(), tables)
+	if _, ok := err.(*ValidationError); !ok {
+		t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+	}
+}
+
+func TestCorrectWorkCard(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+	client.now = func() time.Time { return time.Date(2025, 7, 10, 15, 0, 0, 0, time.UTC) }
+
+	original := SubmissionResponse{
+		ID:             "sub123",
+		Protocol:       "proto456",
+		SubmissionDate: time.Date(2025, 7, 10, 14, 56, 0, 0, time.UTC),
+	}
+	card := WorkCard{
+		EmployeeTaxID:            "123456789",
+		WorkCardMovementType:     Arrival,
+		WorkCardMovementDateTime: DateTime{Time: time.Date(2025, 7, 10, 14, 55, 0, 0, time.UTC)},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		corrections := []CompanyWorkCardCorrection{
+			NewCompanyWorkCardCorrection(original, "999999999", 1,
+				card.Correct(time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC), WrongMovementTime),
+			),
+		}
+
+		responses, err := client.CorrectWorkCard(context.Background(), corrections)
+		if err != nil {
+			t.Fatalf("Expected no error on CorrectWorkCard, but got: %v", err)
+		}
+		if len(responses) != 1 || responses[0].ID != "sub777" {
+			t.Errorf("Expected submission ID 'sub777', got %+v", responses)
+		}
+	})
+
+	t.Run("FutureCorrection", func(t *testing.T) {
+		corrections := []CompanyWorkCardCorrection{
+			NewCompanyWorkCardCorrection(original, "999999999", 1,
+				card.Correct(time.Date(2025, 7, 10, 16, 0, 0, 0, time.UTC), WrongMovementTime),
+			),
+		}
+
+		_, err := client.CorrectWorkCard(context.Background(), corrections)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+		}
+	})
+
+	t.Run("OutsideWindow", func(t *testing.T) {
+		old := card
+		old.WorkCardMovementDateTime = DateTime{Time: time.Date(2025, 7, 8, 9, 0, 0, 0, time.UTC)}
+		corrections := []CompanyWorkCardCorrection{
+			NewCompanyWorkCardCorrection(original, "999999999", 1, old.Cancel(DuplicateMovement)),
+		}
+
+		_, err := client.CorrectWorkCard(context.Background(), corrections)
+		if _, ok := err.(*ValidationError); !ok {
+			t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+		}
+	})
+}
+
+func TestCancelOvertime(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	original := SubmissionResponse{ID: "sub456", Protocol: "proto789", SubmissionDate: time.Date(2025, 7, 11, 10, 0, 0, 0, time.UTC)}
+	submitted := CompanyOvertime{
+		BusinessBranchNumber: 1,
+		EmployeeOvertimes: []Overtime{
+			{EmployeeTaxID: "123456789", OvertimeJustification: ExceptionalWorkload},
+		},
+	}
+
+	responses, err := client.CancelOvertime(context.Background(), original, submitted)
+	if err != nil {
+		t.Fatalf("Expected no error on CancelOvertime, but got: %v", err)
+	}
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 submission response, got %d", len(responses))
+	}
+}
+
+func TestAmendDailySchedule(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+	client.now = func() time.Time { return time.Date(2025, 7, 12, 11, 0, 0, 0, time.UTC) }
+
+	at := func(h int) Time { return Time{Time: time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)} }
+	schedule := func(from int) CompanyDailySchedule {
+		return CompanyDailySchedule{
+			BusinessBranchNumber: 1,
+			EmployeeSchedules: []EmployeeDailySchedule{{
+				EmployeeTaxID:  "123456789",
+				ScheduleDate:   Date{Time: time.Date(2025, 7, 13, 0, 0, 0, 0, time.UTC)},
+				WorkdayDetails: []WorkdayDetails{{WorkType: WorkFromOffice, StartTime: at(from), EndTime: at(from + 8)}},
+			}},
+		}
+	}
+	original := SubmissionResponse{ID: "sub789", Protocol: "proto123", SubmissionDate: time.Date(2025, 7, 12, 11, 0, 0, 0, time.UTC)}
+
+	responses, err := client.AmendDailySchedule(context.Background(), original, schedule(9), schedule(10))
+	if err != nil {
+		t.Fatalf("Expected no error on AmendDailySchedule, but got: %v", err)
+	}
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 submission response, got %d", len(responses))
+	}
+}
+
+func TestGetSubmission(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	submission, err := client.GetSubmission(context.Background(), "proto456")
+	if err != nil {
+		t.Fatalf("Expected no error on GetSubmission, but got: %v", err)
+	}
+	if submission.Protocol != "proto456" || submission.DocumentType != WorkCardDocument {
+		t.Fatalf("Expected work card submission proto456, got %s %s", submission.DocumentType, submission.Protocol)
+	}
+
+	doc, err := submission.CompanyWorkCard()
+	if err != nil {
+		t.Fatalf("Failed to decode work card document: %v", err)
+	}
+	if len(doc.CardDetails) != 1 {
+		t.Fatalf("Expected 1 work card, got %d", len(doc.CardDetails))
+	}
+	card := doc.CardDetails[0]
+	if card.WorkCardMovementType != Arrival {
+		t.Errorf("Expected movement type %s, got %s", Arrival, card.WorkCardMovementType)
+	}
+	if card.LateDeclarationJustification == nil || *card.LateDeclarationJustification != ErganiSystemsUnavailable {
+		t.Errorf("Expected late declaration justification %s, got %v", ErganiSystemsUnavailable, card.LateDeclarationJustification)
+	}
+	if !card.WorkCardMovementDateTime.Equal(time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected movement time %v", card.WorkCardMovementDateTime)
+	}
+
+	if _, err := submission.CompanyOvertime(); err == nil {
+		t.Error("Expected an error when decoding a work card as overtime")
+	}
+}
+
+func TestListSubmissions(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	headquarters := 0
+	submissions, err := client.ListSubmissions(context.Background(), SubmissionFilter{
+		From:                 time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		DocumentType:         OvertimeDocument,
+		BusinessBranchNumber: &headquarters,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error on ListSubmissions, but got: %v", err)
+	}
+	if len(submissions) != 1 {
+		t.Fatalf("Expected 1 submission, got %d", len(submissions))
+	}
+
+	doc, err := submissions[0].CompanyOvertime()
+	if err != nil {
+		t.Fatalf("Failed to decode overtime document: %v", err)
+	}
+	row := doc.EmployeeOvertimes[0]
+	if row.OvertimeJustification != ExceptionalWorkload {
+		t.Errorf("Expected justification %s, got %s", ExceptionalWorkload, row.OvertimeJustification)
+	}
+	if row.OvertimeStartTime.Format("15:04") != "17:00" || row.OvertimeCancellation {
+		t.Errorf("Unexpected overtime row %+v", row)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	entries, err := client.Lookup(context.Background(), ActivityCodesTable)
+	if err != nil {
+		t.Fatalf("Expected no error on Lookup, but got: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Code != "5610" {
+		t.Errorf("Expected a single entry with code 5610, got %+v", entries)
+	}
+}
+
+func TestGetEmployer(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	employer, err := client.GetEmployer(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error on GetEmployer, but got: %v", err)
+	}
+	if employer.EmployerTaxID != "999999999" {
+		t.Errorf("Expected employer tax ID '999999999', got '%s'", employer.EmployerTaxID)
+	}
+
+	branch, ok := employer.Branch(0)
+	if !ok {
+		t.Fatal("Expected branch 0 to exist")
+	}
+
+	header := employer.OvertimeHeader(branch)
+	if header.SEPEServiceCode != "10000" || header.KallikratisCode != "9186" || header.BranchActivityCode != "5610" {
+		t.Errorf("Expected branch codes in the overtime header, got %+v", header)
+	}
+	if header.PrimaryActivityCode != "5610" || header.SecondaryActivityCode1 != "5630" || header.LegalRepTaxID != "111111111" {
+		t.Errorf("Expected employer codes in the overtime header, got %+v", header)
+	}
+}
+
+func TestListBranches(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	branches, err := client.ListBranches(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error on ListBranches, but got: %v", err)
+	}
+	if len(branches) != 2 {
+		t.Fatalf("Expected 2 branches, got %d", len(branches))
+	}
+	if header := branches[1].DailyScheduleHeader(); header.BusinessBranchNumber != 1 {
+		t.Errorf("Expected daily schedule header for branch 1, got %d", header.BusinessBranchNumber)
+	}
+}
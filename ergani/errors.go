@@ -2,6 +2,7 @@ package ergani
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -69,3 +70,47 @@ type AuthenticationError struct {
 func (e *AuthenticationError) Error() string {
 	return fmt.Sprintf("authentication failed: %s", e.Message)
 }
+
+// ValidationError is returned when a document fails a client-side check before
+// it is sent to the API.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the standard error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed for %s: %s", e.Field, e.Message)
+}
+
+// ErrNoScheduleChanges is returned when an amendment is requested for a schedule
+// that is identical to the one already submitted.
+var ErrNoScheduleChanges = errors.New("no schedule changes to amend")
+
+// ErrNotSubmitted is reported for parts of a split submission that were not sent
+// because another part had already failed. Parts sent in parallel may still have
+// been accepted.
+var ErrNotSubmitted = errors.New("not submitted because another request failed")
+
+// ErrNotRecorded is returned together with the responses when Ergani accepted work
+// cards but the DedupStore failed to record them as submitted. The submission
+// succeeded and must not be retried.
+var ErrNotRecorded = errors.New("work cards accepted but not recorded as submitted")
+
+// BatchError summarizes the failed items of a batch submission. It unwraps to the
+// error of the first failed item.
+type BatchError struct {
+	Failed int
+	Total  int
+	First  error
+}
+
+// Error implements the standard error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d batch items failed, first error: %v", e.Failed, e.Total, e.First)
+}
+
+// Unwrap returns the error of the first failed item.
+func (e *BatchError) Unwrap() error {
+	return e.First
+}
@@ -0,0 +1,160 @@
+// Package holidays provides the Greek public holiday calendar, including the
+// moveable feasts that depend on Orthodox Easter and optional local holidays of
+// Kallikratis municipalities.
+package holidays
+
+import (
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Holiday is a public holiday on a specific date.
+type Holiday struct {
+	Date ergani.Date
+	Name string
+	// KallikratisCode is set for local holidays and empty for national ones.
+	KallikratisCode string
+}
+
+// OrthodoxEaster returns the date of Orthodox Easter Sunday in the Gregorian
+// calendar, at midnight UTC. It uses Meeus' Julian algorithm and converts the
+// result from the Julian calendar.
+func OrthodoxEaster(year int) time.Time {
+	a := year % 4
+	b := year % 7
+	c := year % 19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+
+	// The Julian calendar lags the Gregorian one by 13 days between 1900 and 2099.
+	offset := year/100 - year/400 - 2
+	return time.Date(year, time.Month(month), day+offset, 0, 0, 0, 0, time.UTC)
+}
+
+type fixed struct {
+	month time.Month
+	day   int
+	name  string
+}
+
+var fixedHolidays = []fixed{
+	{time.January, 1, "New Year's Day"},
+	{time.January, 6, "Epiphany"},
+	{time.March, 25, "Independence Day"},
+	{time.May, 1, "Labour Day"},
+	{time.August, 15, "Assumption of Mary"},
+	{time.October, 28, "Ochi Day"},
+	{time.December, 25, "Christmas Day"},
+	{time.December, 26, "Synaxis of the Mother of God"},
+}
+
+type moveable struct {
+	offset int
+	name   string
+}
+
+// moveableHolidays are relative to Orthodox Easter Sunday.
+var moveableHolidays = []moveable{
+	{-48, "Clean Monday"},
+	{-2, "Good Friday"},
+	{0, "Easter Sunday"},
+	{1, "Easter Monday"},
+	{50, "Whit Monday"},
+}
+
+// National returns the national public holidays of a year in date order.
+func National(year int) []Holiday {
+	var holidays []Holiday
+	for _, f := range fixedHolidays {
+		holidays = append(holidays, Holiday{Date: date(year, f.month, f.day), Name: f.name})
+	}
+	easter := OrthodoxEaster(year)
+	for _, m := range moveableHolidays {
+		holidays = append(holidays, Holiday{Date: ergani.Date{Time: easter.AddDate(0, 0, m.offset)}, Name: m.name})
+	}
+	sortHolidays(holidays)
+	return holidays
+}
+
+// LocalHoliday is a holiday observed in a single municipality, e.g. the feast of
+// its patron saint. It falls either on a fixed Month and Day or, if Month is zero,
+// EasterOffset days after Orthodox Easter Sunday.
+type LocalHoliday struct {
+	KallikratisCode string
+	Name            string
+	Month           time.Month
+	Day             int
+	EasterOffset    int
+}
+
+// on returns the date of the holiday in a year.
+func (l LocalHoliday) on(year int) ergani.Date {
+	if l.Month == 0 {
+		return ergani.Date{Time: OrthodoxEaster(year).AddDate(0, 0, l.EasterOffset)}
+	}
+	return date(year, l.Month, l.Day)
+}
+
+// Calendar combines the national holidays with local holidays.
+type Calendar struct {
+	local map[string][]LocalHoliday
+}
+
+// NewCalendar creates a Calendar with the given local holidays.
+func NewCalendar(local ...LocalHoliday) *Calendar {
+	c := &Calendar{local: make(map[string][]LocalHoliday)}
+	for _, l := range local {
+		c.local[l.KallikratisCode] = append(c.local[l.KallikratisCode], l)
+	}
+	return c
+}
+
+// Holidays returns the national holidays of a year together with the local
+// holidays of the municipality, in date order. An empty kallikratisCode returns
+// the national holidays only.
+func (c *Calendar) Holidays(year int, kallikratisCode string) []Holiday {
+	holidays := National(year)
+	if kallikratisCode == "" {
+		return holidays
+	}
+	for _, l := range c.local[kallikratisCode] {
+		holidays = append(holidays, Holiday{Date: l.on(year), Name: l.Name, KallikratisCode: l.KallikratisCode})
+	}
+	sortHolidays(holidays)
+	return holidays
+}
+
+// IsHoliday reports whether the date is a national holiday or a local holiday of
+// the municipality and returns the holiday.
+func (c *Calendar) IsHoliday(d ergani.Date, kallikratisCode string) (Holiday, bool) {
+	for _, h := range c.Holidays(d.Year(), kallikratisCode) {
+		if sameDay(h.Date.Time, d.Time) {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// IsHoliday reports whether the date is a national holiday and returns it.
+func IsHoliday(d ergani.Date) (Holiday, bool) {
+	return NewCalendar().IsHoliday(d, "")
+}
+
+func date(year int, month time.Month, day int) ergani.Date {
+	return ergani.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// sameDay compares calendar dates regardless of time and location.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func sortHolidays(holidays []Holiday) {
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date.Time) })
+}
@@ -0,0 +1,69 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+func TestOrthodoxEaster(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+	}{
+		{2021, time.May, 2},
+		{2023, time.April, 16},
+		{2024, time.May, 5},
+		{2025, time.April, 20},
+		{2026, time.April, 12},
+	}
+	for _, tt := range tests {
+		got := OrthodoxEaster(tt.year)
+		if got.Month() != tt.month || got.Day() != tt.day {
+			t.Errorf("Expected Orthodox Easter %d on %s %d, got %s", tt.year, tt.month, tt.day, got.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	day := func(month time.Month, d int) ergani.Date {
+		return ergani.Date{Time: time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		date ergani.Date
+		want string
+	}{
+		{day(time.March, 3), "Clean Monday"},
+		{day(time.April, 18), "Good Friday"},
+		{day(time.April, 21), "Easter Monday"},
+		{day(time.June, 9), "Whit Monday"},
+		{day(time.October, 28), "Ochi Day"},
+		{day(time.October, 29), ""},
+	}
+	for _, tt := range tests {
+		h, ok := IsHoliday(tt.date)
+		if ok != (tt.want != "") || h.Name != tt.want {
+			t.Errorf("Expected %s to be %q, got %q", tt.date.Format("02/01/2006"), tt.want, h.Name)
+		}
+	}
+
+	calendar := NewCalendar(
+		LocalHoliday{KallikratisCode: "9186", Name: "Saint Dionysius", Month: time.October, Day: 3},
+		LocalHoliday{KallikratisCode: "9186", Name: "Local feast", EasterOffset: 3},
+	)
+	if h, ok := calendar.IsHoliday(day(time.October, 3), "9186"); !ok || h.KallikratisCode != "9186" {
+		t.Errorf("Expected a local holiday, got %+v", h)
+	}
+	if _, ok := calendar.IsHoliday(day(time.April, 23), "9186"); !ok {
+		t.Error("Expected a moveable local holiday three days after Easter")
+	}
+	if _, ok := calendar.IsHoliday(day(time.October, 3), "1111"); ok {
+		t.Error("Expected local holidays not to apply to other municipalities")
+	}
+	if n := len(calendar.Holidays(2025, "9186")); n != len(National(2025))+2 {
+		t.Errorf("Expected national and local holidays, got %d", n)
+	}
+}
@@ -0,0 +1,26 @@
+// Package testutil holds the fixtures shared by the tests of the ergani packages.
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Clock parses a time of day in the "15:04" format and fails the test if it is
+// invalid.
+func Clock(t testing.TB, s string) ergani.Time {
+	t.Helper()
+	c, err := time.Parse("15:04", s)
+	if err != nil {
+		t.Fatalf("Invalid time of day %q: %v", s, err)
+	}
+	return ergani.Time{Time: c}
+}
+
+// Workday returns a period of workType from one time of day to another.
+func Workday(t testing.TB, workType ergani.ScheduleWorkType, from, to string) ergani.WorkdayDetails {
+	t.Helper()
+	return ergani.WorkdayDetails{WorkType: workType, StartTime: Clock(t, from), EndTime: Clock(t, to)}
+}
@@ -0,0 +1,89 @@
+package ergani
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultLateDeclarationWindow is the default delay after a movement within which
+// a work card entry is still considered a real-time declaration.
+const DefaultLateDeclarationWindow = 15 * time.Minute
+
+// LateDeclarationPolicy justifies work card entries that are submitted after the
+// real-time window, e.g. when they are replayed after an outage.
+type LateDeclarationPolicy struct {
+	// Window is how long after WorkCardMovementDateTime an entry may be submitted
+	// without a justification. Defaults to DefaultLateDeclarationWindow.
+	Window time.Duration
+	// Justification is applied to late entries that have none. It is overridden by
+	// WithLateJustification. If both are empty late entries are only reported.
+	Justification LateDeclarationJustificationType
+	// OnLate, if set, is called for every late entry that had no justification.
+	OnLate func(LateDeclaration)
+}
+
+// LateDeclaration describes a late work card entry detected by a LateDeclarationPolicy.
+type LateDeclaration struct {
+	EmployerTaxID        string
+	BusinessBranchNumber int
+	// WorkCard is the entry as submitted, including the applied justification.
+	WorkCard WorkCard
+	// Delay is the time between the movement and the submission.
+	Delay time.Duration
+	// Justification is the applied reason, or empty if none was configured.
+	Justification LateDeclarationJustificationType
+}
+
+type lateJustificationKey struct{}
+
+// WithLateJustification returns a context that makes SubmitWorkCard justify late
+// entries with j, e.g. ErganiSystemsUnavailable when replaying work cards after an
+// Ergani outage. It takes precedence over the configured policy and applies even if
+// no policy is configured.
+func WithLateJustification(ctx context.Context, j LateDeclarationJustificationType) context.Context {
+	return context.WithValue(ctx, lateJustificationKey{}, j)
+}
+
+// applyLateDeclarationPolicy returns the work cards with late entries justified.
+// The input is not modified.
+func (c *Client) applyLateDeclarationPolicy(ctx context.Context, companyWorkCards []CompanyWorkCard) []CompanyWorkCard {
+	var policy LateDeclarationPolicy
+	if c.lateDeclarationPolicy != nil {
+		policy = *c.lateDeclarationPolicy
+	}
+	if j, ok := ctx.Value(lateJustificationKey{}).(LateDeclarationJustificationType); ok {
+		policy.Justification = j
+	} else if c.lateDeclarationPolicy == nil {
+		return companyWorkCards
+	}
+	if policy.Window == 0 {
+		policy.Window = DefaultLateDeclarationWindow
+	}
+
+	now := c.now()
+	result := make([]CompanyWorkCard, len(companyWorkCards))
+	for i, cwc := range companyWorkCards {
+		result[i] = cwc
+		result[i].CardDetails = make([]WorkCard, len(cwc.CardDetails))
+		for k, card := range cwc.CardDetails {
+			delay := now.Sub(card.WorkCardMovementDateTime.Time)
+			if card.LateDeclarationJustification == nil && delay > policy.Window {
+				if policy.Justification != "" {
+					j := policy.Justification
+					card.LateDeclarationJustification = &j
+				}
+				if policy.OnLate != nil {
+					policy.OnLate(LateDeclaration{
+						EmployerTaxID:        cwc.EmployerTaxID,
+						BusinessBranchNumber: cwc.BusinessBranchNumber,
+						WorkCard:             card,
+						Delay:                delay,
+						Justification:        policy.Justification,
+					})
+				}
+			}
+			result[i].CardDetails[k] = card
+		}
+	}
+	return result
+}
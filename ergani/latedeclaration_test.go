@@ -0,0 +1,75 @@
+package ergani
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestApplyLateDeclarationPolicy(t *testing.T) {
+	now := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+	powerOutage := PowerOutage
+	cards := []CompanyWorkCard{{
+		EmployerTaxID:        "999999999",
+		BusinessBranchNumber: 1,
+		CardDetails: []WorkCard{
+			{EmployeeTaxID: "111111111", WorkCardMovementDateTime: DateTime{Time: now.Add(-5 * time.Minute)}},
+			{EmployeeTaxID: "222222222", WorkCardMovementDateTime: DateTime{Time: now.Add(-2 * time.Hour)}},
+			{EmployeeTaxID: "333333333", WorkCardMovementDateTime: DateTime{Time: now.Add(-2 * time.Hour)}, LateDeclarationJustification: &powerOutage},
+		},
+	}}
+
+	var detected []LateDeclaration
+	client := &Client{
+		now: func() time.Time { return now },
+		lateDeclarationPolicy: &LateDeclarationPolicy{
+			Justification: EmployerSystemsUnavailable,
+			OnLate:        func(d LateDeclaration) { detected = append(detected, d) },
+		},
+	}
+
+	t.Run("Policy", func(t *testing.T) {
+		detected = nil
+		got := client.applyLateDeclarationPolicy(context.Background(), cards)
+
+		details := got[0].CardDetails
+		if details[0].LateDeclarationJustification != nil {
+			t.Errorf("Expected real-time entry to stay unjustified, got %v", *details[0].LateDeclarationJustification)
+		}
+		if details[1].LateDeclarationJustification == nil || *details[1].LateDeclarationJustification != EmployerSystemsUnavailable {
+			t.Errorf("Expected late entry to be justified with %s, got %v", EmployerSystemsUnavailable, details[1].LateDeclarationJustification)
+		}
+		if *details[2].LateDeclarationJustification != PowerOutage {
+			t.Errorf("Expected existing justification to be kept, got %s", *details[2].LateDeclarationJustification)
+		}
+		if cards[0].CardDetails[1].LateDeclarationJustification != nil {
+			t.Error("Expected input work cards not to be modified")
+		}
+
+		if len(detected) != 1 || detected[0].WorkCard.EmployeeTaxID != "222222222" ||
+			detected[0].Delay != 2*time.Hour || detected[0].BusinessBranchNumber != 1 {
+			t.Errorf("Expected one late declaration for 222222222, got %+v", detected)
+		}
+	})
+
+	t.Run("ContextOverride", func(t *testing.T) {
+		detected = nil
+		ctx := WithLateJustification(context.Background(), ErganiSystemsUnavailable)
+		got := client.applyLateDeclarationPolicy(ctx, cards)
+
+		if j := got[0].CardDetails[1].LateDeclarationJustification; j == nil || *j != ErganiSystemsUnavailable {
+			t.Errorf("Expected context justification %s, got %v", ErganiSystemsUnavailable, j)
+		}
+		if len(detected) != 1 || detected[0].Justification != ErganiSystemsUnavailable {
+			t.Errorf("Expected the applied justification to be recorded, got %+v", detected)
+		}
+	})
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		client := &Client{now: func() time.Time { return now }}
+		got := client.applyLateDeclarationPolicy(context.Background(), cards)
+		if got[0].CardDetails[1].LateDeclarationJustification != nil {
+			t.Error("Expected no justification without a policy")
+		}
+	})
+}
@@ -25,6 +25,32 @@ type CompanyWorkCard struct {
 	CardDetails []WorkCard `json:"Details>CardDetails"`
 }
 
+// WorkCardCorrection represents the correction or cancellation of a single work card
+// entry that was already submitted.
+type WorkCardCorrection struct {
+	EmployeeTaxID            string                 `json:"f_afm"`
+	EmployeeLastName         string                 `json:"f_eponymo"`
+	EmployeeFirstName        string                 `json:"f_onoma"`
+	CorrectionType           WorkCardCorrectionType `json:"f_correction_type"`
+	WorkCardMovementType     WorkCardMovementType   `json:"f_type"`
+	OriginalMovementDateTime DateTime               `json:"f_original_date"`
+	// CorrectedMovementDateTime is required for corrections and must be omitted for cancellations.
+	CorrectedMovementDateTime *DateTime                   `json:"f_date,omitempty"`
+	Justification             CorrectionJustificationType `json:"f_aitiologia"`
+}
+
+// CompanyWorkCardCorrection groups work card corrections for a single business branch.
+// It references the protocol of the original work card submission.
+type CompanyWorkCardCorrection struct {
+	EmployerTaxID        string `json:"f_afm_ergodoti"`
+	BusinessBranchNumber int    `json:"f_aa"`
+	RelatedProtocolID    string `json:"f_rel_protocol"`
+	RelatedProtocolDate  Date   `json:"f_rel_date"`
+	Comments             string `json:"f_comments,omitempty"`
+	// Corrections are nested within "Details>CorrectionDetails" in the final JSON.
+	Corrections []WorkCardCorrection `json:"Details>CorrectionDetails"`
+}
+
 // Overtime represents an overtime entry for an employee on a specific date.
 type Overtime struct {
 	EmployeeTaxID          string                    `json:"f_afm"`
@@ -114,6 +140,100 @@ type CompanyWeeklySchedule struct {
 	Comments            string                   `json:"f_comments,omitempty"`
 }
 
+// EmploymentChange represents a change to an employee's working terms, such as a
+// conversion from full-time to part-time or a new weekly working time.
+type EmploymentChange struct {
+	EmployeeTaxID          string               `json:"f_afm"`
+	EmployeeSSN            string               `json:"f_amka"`
+	EmployeeLastName       string               `json:"f_eponymo"`
+	EmployeeFirstName      string               `json:"f_onoma"`
+	ChangeType             EmploymentChangeType `json:"f_metavoli"`
+	ChangeDate             Date                 `json:"f_date"`
+	ContractType           ContractType         `json:"f_sxesi"`
+	WeeklyHours            float64              `json:"f_wres_ebdomadas"`
+	EmployeeProfessionCode string               `json:"f_step,omitempty"`
+	Comments               string               `json:"f_comments,omitempty"`
+}
+
+// CompanyEmploymentChange groups employment changes for a single business branch.
+type CompanyEmploymentChange struct {
+	BusinessBranchNumber int `json:"f_aa_pararthmatos"`
+	// EmployeeChanges are nested within "Ergazomenoi>ErgazomenosE9".
+	EmployeeChanges     []EmploymentChange `json:"Ergazomenoi>ErgazomenosE9"`
+	RelatedProtocolID   string             `json:"f_rel_protocol,omitempty"`
+	RelatedProtocolDate *Date              `json:"f_rel_date,omitempty"`
+	Comments            string             `json:"f_comments,omitempty"`
+}
+
+// StaffTableDay describes an employee's working hours and breaks on one weekday of
+// the annual staff table.
+type StaffTableDay struct {
+	Day Weekday `json:"f_day"`
+	// WorkdayDetails are nested within "ErgazomenosAnalytics>ErgazomenosWTOAnalytics".
+	WorkdayDetails []WorkdayDetails `json:"ErgazomenosAnalytics>ErgazomenosWTOAnalytics"`
+	BreakMinutes   int              `json:"f_break"`
+}
+
+// StaffTableEmployee represents an employee's entry in the annual staff table,
+// including the weekly working time and pay.
+type StaffTableEmployee struct {
+	EmployeeTaxID          string       `json:"f_afm"`
+	EmployeeSSN            string       `json:"f_amka"`
+	EmployeeLastName       string       `json:"f_eponymo"`
+	EmployeeFirstName      string       `json:"f_onoma"`
+	EmployeeProfessionCode string       `json:"f_step"`
+	ContractType           ContractType `json:"f_sxesi"`
+	WeeklyHours            float64      `json:"f_wres_ebdomadas"`
+	GrossPay               float64      `json:"f_apodoxes"`
+	// Days are nested within "Hmeres>HmeraE4".
+	Days []StaffTableDay `json:"Hmeres>HmeraE4"`
+}
+
+// CompanyAnnualStaffTable represents the annual staff table (E4) of a business branch.
+// Setting RelatedProtocolID and RelatedProtocolDate turns it into a supplementary
+// (amending) table for a previously submitted one.
+type CompanyAnnualStaffTable struct {
+	BusinessBranchNumber int `json:"f_aa_pararthmatos"`
+	Year                 int `json:"f_year"`
+	// Employees are nested within "Ergazomenoi>ErgazomenosE4".
+	Employees           []StaffTableEmployee `json:"Ergazomenoi>ErgazomenosE4"`
+	RelatedProtocolID   string               `json:"f_rel_protocol,omitempty"`
+	RelatedProtocolDate *Date                `json:"f_rel_date,omitempty"`
+	Comments            string               `json:"f_comments,omitempty"`
+}
+
+// Branch represents a business branch of the employer as registered in Ergani.
+type Branch struct {
+	BusinessBranchNumber int    `json:"f_aa_pararthmatos"`
+	Name                 string `json:"f_perigrafi"`
+	Address              string `json:"f_dieythynsi"`
+	PostalCode           string `json:"f_tk"`
+	ActivityCode         string `json:"f_kad_pararthmatos"`
+	KallikratisCode      string `json:"f_kallikratis_pararthmatos"`
+	SEPEServiceCode      string `json:"f_ypiresia_sepe"`
+}
+
+// Employer represents the authenticated employer and its registered branches.
+type Employer struct {
+	EmployerTaxID          string   `json:"f_afm_ergodoti"`
+	Name                   string   `json:"f_eponymia"`
+	LegalRepTaxID          string   `json:"f_afm_proswpoy"`
+	PrimaryActivityCode    string   `json:"f_kad_kyria"`
+	SecondaryActivityCodes []string `json:"f_kad_deyt"`
+	EmployerOrganization   string   `json:"f_ergodotikh_organwsh,omitempty"`
+	// Branches are nested within "Parartimata>Parartima".
+	Branches []Branch `json:"Parartimata>Parartima"`
+}
+
+// RegisteredEmployee represents an employee exactly as registered in Ergani.
+type RegisteredEmployee struct {
+	EmployeeTaxID        string `json:"f_afm"`
+	EmployeeSSN          string `json:"f_amka"`
+	EmployeeLastName     string `json:"f_eponymo"`
+	EmployeeFirstName    string `json:"f_onoma"`
+	BusinessBranchNumber int    `json:"f_aa_pararthmatos"`
+}
+
 // SubmissionResponse represents the data returned from a successful submission to the API.
 type SubmissionResponse struct {
 	ID       string `json:"id"`
@@ -122,6 +242,20 @@ type SubmissionResponse struct {
 	SubmissionDate time.Time `json:"-"`
 }
 
+// MarshalJSON encodes the SubmissionDate field in the API's date format, so that
+// stored responses can be decoded again with UnmarshalJSON.
+func (s SubmissionResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID             string `json:"id"`
+		Protocol       string `json:"protocol"`
+		SubmissionDate string `json:"submitDate"`
+	}{
+		ID:             s.ID,
+		Protocol:       s.Protocol,
+		SubmissionDate: s.SubmissionDate.Format("02/01/2006 15:04"),
+	})
+}
+
 // UnmarshalJSON handles the custom date format ("02/01/2006 15:04") from the API
 // for the SubmissionDate field.
 func (s *SubmissionResponse) UnmarshalJSON(data []byte) error {
@@ -152,3 +286,54 @@ func (s *SubmissionResponse) UnmarshalJSON(data []byte) error {
 	s.SubmissionDate = t
 	return nil
 }
+
+// Submission represents a previously submitted document as returned by the API.
+// The document body is kept raw and decoded on demand into the matching model
+// (see CompanyWorkCard, CompanyOvertime and the other accessors).
+type Submission struct {
+	SubmissionResponse
+	DocumentType         DocumentType
+	BusinessBranchNumber int
+	Document             json.RawMessage
+}
+
+// MarshalJSON encodes the submission in the form UnmarshalJSON reads. Without it
+// the MarshalJSON of the embedded SubmissionResponse would drop the document.
+func (s Submission) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID                   string          `json:"id"`
+		Protocol             string          `json:"protocol"`
+		SubmissionDate       string          `json:"submitDate"`
+		DocumentType         DocumentType    `json:"type"`
+		BusinessBranchNumber int             `json:"branch"`
+		Document             json.RawMessage `json:"document,omitempty"`
+	}{
+		ID:                   s.ID,
+		Protocol:             s.Protocol,
+		SubmissionDate:       s.SubmissionDate.Format("02/01/2006 15:04"),
+		DocumentType:         s.DocumentType,
+		BusinessBranchNumber: s.BusinessBranchNumber,
+		Document:             s.Document,
+	})
+}
+
+// UnmarshalJSON decodes the submission metadata and keeps the document body raw.
+func (s *Submission) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.SubmissionResponse); err != nil {
+		return err
+	}
+
+	var aux struct {
+		DocumentType         DocumentType    `json:"type"`
+		BusinessBranchNumber int             `json:"branch"`
+		Document             json.RawMessage `json:"document"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	s.DocumentType = aux.DocumentType
+	s.BusinessBranchNumber = aux.BusinessBranchNumber
+	s.Document = aux.Document
+	return nil
+}
@@ -61,3 +61,96 @@ func TestCompanyWorkCard_MarshalJSON(t *testing.T) {
 		t.Error("Expected to find correctly formatted date")
 	}
 }
+
+func TestWorkCardCorrection_MarshalJSON(t *testing.T) {
+	card := WorkCard{
+		EmployeeTaxID:            "123456789",
+		WorkCardMovementType:     Departure,
+		WorkCardMovementDateTime: DateTime{Time: time.Date(2025, 7, 10, 17, 0, 0, 0, time.UTC)},
+	}
+
+	bytes, err := json.Marshal(card.Cancel(DuplicateMovement))
+	if err != nil {
+		t.Fatalf("Failed to marshal WorkCardCorrection: %v", err)
+	}
+
+	jsonString := string(bytes)
+
+	if !strings.Contains(jsonString, `"f_correction_type":"2"`) {
+		t.Error("Expected to find marshaled cancellation type '2'")
+	}
+	if !strings.Contains(jsonString, `"f_type":"1"`) {
+		t.Error("Expected to find marshaled work card movement type '1'")
+	}
+	if !strings.Contains(jsonString, `"f_aitiologia":"002"`) {
+		t.Error("Expected to find marshaled correction justification '002'")
+	}
+	if strings.Contains(jsonString, `"f_date"`) {
+		t.Error("Expected a cancellation to omit the corrected movement time")
+	}
+}
+
+func TestCompanyDailySchedule_RoundTrip(t *testing.T) {
+	schedule := CompanyDailySchedule{
+		BusinessBranchNumber: 2,
+		EmployeeSchedules: []EmployeeDailySchedule{
+			{
+				EmployeeTaxID: "123456789",
+				ScheduleDate:  Date{Time: time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC)},
+				WorkdayDetails: []WorkdayDetails{
+					{WorkType: WorkFromHome, StartTime: Time{Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)}, EndTime: Time{Time: time.Date(0, 1, 1, 13, 0, 0, 0, time.UTC)}},
+				},
+			},
+		},
+	}
+
+	bytes, err := json.Marshal(schedule)
+	if err != nil {
+		t.Fatalf("Failed to marshal CompanyDailySchedule: %v", err)
+	}
+
+	var decoded CompanyDailySchedule
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal CompanyDailySchedule: %v", err)
+	}
+
+	es := decoded.EmployeeSchedules[0]
+	if !es.ScheduleDate.Equal(schedule.EmployeeSchedules[0].ScheduleDate.Time) {
+		t.Errorf("Expected date %v, got %v", schedule.EmployeeSchedules[0].ScheduleDate, es.ScheduleDate)
+	}
+	if es.WorkdayDetails[0].WorkType != WorkFromHome {
+		t.Errorf("Expected work type %s, got %s", WorkFromHome, es.WorkdayDetails[0].WorkType)
+	}
+	if es.WorkdayDetails[0].EndTime.Format("15:04") != "13:00" {
+		t.Errorf("Expected end time 13:00, got %s", es.WorkdayDetails[0].EndTime.Format("15:04"))
+	}
+}
+
+func TestSubmission_RoundTrip(t *testing.T) {
+	submission := Submission{
+		SubmissionResponse: SubmissionResponse{
+			ID:             "sub1",
+			Protocol:       "proto1",
+			SubmissionDate: time.Date(2025, 7, 10, 14, 56, 0, 0, time.UTC),
+		},
+		DocumentType:         WorkCardDocument,
+		BusinessBranchNumber: 2,
+		Document:             json.RawMessage(`{"f_afm_ergodoti":"999999999"}`),
+	}
+
+	bytes, err := json.Marshal(submission)
+	if err != nil {
+		t.Fatalf("Failed to marshal Submission: %v", err)
+	}
+
+	var decoded Submission
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal Submission: %v", err)
+	}
+	if decoded.ID != "sub1" || decoded.Protocol != "proto1" || !decoded.SubmissionDate.Equal(submission.SubmissionDate) {
+		t.Errorf("Expected the response fields to survive, got %+v", decoded.SubmissionResponse)
+	}
+	if decoded.DocumentType != WorkCardDocument || decoded.BusinessBranchNumber != 2 || string(decoded.Document) != string(submission.Document) {
+		t.Errorf("Expected the document fields to survive, got %s", bytes)
+	}
+}
@@ -0,0 +1,230 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FileStore is a Store backed by an append-only write-ahead log. Every change is
+// appended to the file as a JSON line and synced to disk before it is acknowledged;
+// the latest record of each item wins when the log is replayed.
+type FileStore struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	items  map[int64]Item
+	lastID int64
+}
+
+// OpenFileStore opens the log at path, creating it if needed, and replays it. A
+// truncated final record, left by a crash during a write, is ignored.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, items: make(map[int64]Item)}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox log: %w", err)
+	}
+	s.file = file
+	return s, nil
+}
+
+func (s *FileStore) replay() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open outbox log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	var (
+		offset  int64
+		corrupt error
+	)
+	for scanner.Scan() {
+		if corrupt != nil {
+			// Only the last record may be incomplete.
+			return corrupt
+		}
+		var item Item
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			corrupt = fmt.Errorf("corrupt outbox log record: %w", err)
+			continue
+		}
+		offset += int64(len(scanner.Bytes())) + 1
+		s.items[item.ID] = item
+		if item.ID > s.lastID {
+			s.lastID = item.ID
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read outbox log: %w", err)
+	}
+
+	if corrupt != nil {
+		// Drop the truncated record so that new records start on a fresh line.
+		if err := os.Truncate(s.path, offset); err != nil {
+			return fmt.Errorf("failed to repair outbox log: %w", err)
+		}
+	}
+	return nil
+}
+
+// Add implements Store.
+func (s *FileStore) Add(_ context.Context, item *Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item.ID = s.lastID + 1
+	if err := s.write(*item); err != nil {
+		return err
+	}
+	s.lastID = item.ID
+	s.items[item.ID] = *item
+	return nil
+}
+
+// Update implements Store.
+func (s *FileStore) Update(_ context.Context, item Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[item.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := s.write(item); err != nil {
+		return err
+	}
+	s.items[item.ID] = item
+	return nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(_ context.Context, id int64) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+	return item, nil
+}
+
+// List implements Store.
+func (s *FileStore) List(_ context.Context, status Status) ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []Item
+	for _, item := range s.items {
+		if item.Status == status {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// Compact rewrites the log with a single record per item and drops submitted
+// items, whose status is no longer available afterwards. The most recent item is
+// always kept so that IDs are not reused.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to compact outbox log: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	ids := make([]int64, 0, len(s.items))
+	for id, item := range s.items {
+		if item.Status != StatusSubmitted || id == s.lastID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	w := bufio.NewWriter(tmp)
+	for _, id := range ids {
+		line, err := json.Marshal(s.items[id])
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to encode outbox item: %w", err)
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact outbox log: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact outbox log: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact outbox log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to compact outbox log: %w", err)
+	}
+
+	// The new log is opened before it replaces the old one, so that the store
+	// keeps using the old log if the replacement fails.
+	file, err := os.OpenFile(tmp.Name(), os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open outbox log: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to replace outbox log: %w", err)
+	}
+	old := s.file
+	s.file = file
+
+	for id, item := range s.items {
+		if item.Status == StatusSubmitted && id != s.lastID {
+			delete(s.items, id)
+		}
+	}
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("failed to close outbox log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying log file.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+func (s *FileStore) write(item Item) error {
+	line, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox item: %w", err)
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox log: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox log: %w", err)
+	}
+	return nil
+}
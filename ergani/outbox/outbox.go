@@ -0,0 +1,183 @@
+// Package outbox persists pending Ergani submissions to a local store, so that
+// declarations made while Ergani is unreachable are not lost, and drains them
+// through an ergani.Client once it is available again.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Kind identifies the type of document held by an outbox item.
+type Kind string
+
+const (
+	KindWorkCard       Kind = "work_card"
+	KindOvertime       Kind = "overtime"
+	KindDailySchedule  Kind = "daily_schedule"
+	KindWeeklySchedule Kind = "weekly_schedule"
+)
+
+// Status is the delivery state of an outbox item.
+type Status string
+
+const (
+	// StatusPending items are waiting to be submitted, possibly after a failed attempt.
+	StatusPending Status = "pending"
+	// StatusSubmitted items were accepted by Ergani.
+	StatusSubmitted Status = "submitted"
+	// StatusFailed items were rejected by Ergani or ran out of attempts and need
+	// manual attention.
+	StatusFailed Status = "failed"
+)
+
+// ErrNotFound is returned by a Store when an item does not exist.
+var ErrNotFound = errors.New("outbox item not found")
+
+// Item is a single pending submission together with its delivery state.
+type Item struct {
+	ID   int64 `json:"id"`
+	Kind Kind  `json:"kind"`
+	// Keys identify the employees the document refers to. Items sharing a key are
+	// submitted in the order they were enqueued.
+	Keys []string `json:"keys"`
+	// Payload is the JSON encoded company document.
+	Payload json.RawMessage `json:"payload"`
+
+	Status    Status `json:"status"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+	// ErganiUnavailable reports that the last attempt failed because Ergani
+	// answered with a server error or rate limiting.
+	ErganiUnavailable bool                        `json:"ergani_unavailable,omitempty"`
+	Responses         []ergani.SubmissionResponse `json:"responses,omitempty"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	UpdatedAt         time.Time                   `json:"updated_at"`
+	NextAttempt       time.Time                   `json:"next_attempt"`
+}
+
+// Store persists outbox items.
+type Store interface {
+	// Add stores a new item and assigns its ID.
+	Add(ctx context.Context, item *Item) error
+	// Update replaces the stored item with the same ID.
+	Update(ctx context.Context, item Item) error
+	// Get returns the item with the given ID or ErrNotFound.
+	Get(ctx context.Context, id int64) (Item, error)
+	// List returns the items with the given status in the order they were added.
+	List(ctx context.Context, status Status) ([]Item, error)
+}
+
+// Outbox enqueues documents into a Store.
+type Outbox struct {
+	store Store
+	// now returns the current time and is replaced in tests.
+	now func() time.Time
+}
+
+// New creates an Outbox backed by store.
+func New(store Store) *Outbox {
+	return &Outbox{store: store, now: time.Now}
+}
+
+// EnqueueWorkCard stores the work cards of a branch, one item per employee, so that
+// a rejected entry does not hold back the movements of other employees.
+func (o *Outbox) EnqueueWorkCard(ctx context.Context, companyWorkCard ergani.CompanyWorkCard) ([]Item, error) {
+	var order []string
+	cards := make(map[string][]ergani.WorkCard)
+	for _, card := range companyWorkCard.CardDetails {
+		if _, ok := cards[card.EmployeeTaxID]; !ok {
+			order = append(order, card.EmployeeTaxID)
+		}
+		cards[card.EmployeeTaxID] = append(cards[card.EmployeeTaxID], card)
+	}
+
+	items := make([]Item, 0, len(order))
+	for _, taxID := range order {
+		doc := companyWorkCard
+		doc.CardDetails = cards[taxID]
+
+		item, err := o.enqueue(ctx, KindWorkCard, []string{taxID}, doc)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// EnqueueOvertime stores an overtime declaration.
+func (o *Outbox) EnqueueOvertime(ctx context.Context, companyOvertime ergani.CompanyOvertime) (Item, error) {
+	keys := make([]string, 0, len(companyOvertime.EmployeeOvertimes))
+	for _, ot := range companyOvertime.EmployeeOvertimes {
+		keys = append(keys, ot.EmployeeTaxID)
+	}
+	return o.enqueue(ctx, KindOvertime, keys, companyOvertime)
+}
+
+// EnqueueDailySchedule stores a daily schedule declaration.
+func (o *Outbox) EnqueueDailySchedule(ctx context.Context, companyDailySchedule ergani.CompanyDailySchedule) (Item, error) {
+	keys := make([]string, 0, len(companyDailySchedule.EmployeeSchedules))
+	for _, s := range companyDailySchedule.EmployeeSchedules {
+		keys = append(keys, s.EmployeeTaxID)
+	}
+	return o.enqueue(ctx, KindDailySchedule, keys, companyDailySchedule)
+}
+
+// EnqueueWeeklySchedule stores a weekly schedule declaration.
+func (o *Outbox) EnqueueWeeklySchedule(ctx context.Context, companyWeeklySchedule ergani.CompanyWeeklySchedule) (Item, error) {
+	keys := make([]string, 0, len(companyWeeklySchedule.EmployeeSchedules))
+	for _, s := range companyWeeklySchedule.EmployeeSchedules {
+		keys = append(keys, s.EmployeeTaxID)
+	}
+	return o.enqueue(ctx, KindWeeklySchedule, keys, companyWeeklySchedule)
+}
+
+// Status returns the current state of an enqueued item.
+func (o *Outbox) Status(ctx context.Context, id int64) (Item, error) {
+	return o.store.Get(ctx, id)
+}
+
+// Items returns the items with the given status in the order they were enqueued.
+func (o *Outbox) Items(ctx context.Context, status Status) ([]Item, error) {
+	return o.store.List(ctx, status)
+}
+
+func (o *Outbox) enqueue(ctx context.Context, kind Kind, keys []string, doc interface{}) (Item, error) {
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return Item{}, fmt.Errorf("failed to encode %s: %w", kind, err)
+	}
+
+	now := o.now()
+	item := Item{
+		Kind:        kind,
+		Keys:        uniqueKeys(keys),
+		Payload:     payload,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		NextAttempt: now,
+	}
+	if err := o.store.Add(ctx, &item); err != nil {
+		return Item{}, fmt.Errorf("failed to store %s: %w", kind, err)
+	}
+	return item, nil
+}
+
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	unique := keys[:0]
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+func testWorkCard(taxID string, movement ergani.WorkCardMovementType, at time.Time) ergani.WorkCard {
+	return ergani.WorkCard{
+		EmployeeTaxID:            taxID,
+		EmployeeLastName:         "Doe",
+		EmployeeFirstName:        "John",
+		WorkCardMovementType:     movement,
+		WorkCardSubmissionDate:   ergani.Date{Time: at.Truncate(24 * time.Hour)},
+		WorkCardMovementDateTime: ergani.DateTime{Time: at},
+	}
+}
+
+func TestFileStoreReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "outbox.log")
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	o := New(store)
+
+	at := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	items, err := o.EnqueueWorkCard(ctx, ergani.CompanyWorkCard{
+		EmployerTaxID:        "999999999",
+		BusinessBranchNumber: 1,
+		CardDetails: []ergani.WorkCard{
+			testWorkCard("111111111", ergani.Arrival, at),
+			testWorkCard("222222222", ergani.Arrival, at),
+			testWorkCard("111111111", ergani.Departure, at.Add(8*time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	if len(items) != 2 || items[0].Keys[0] != "111111111" {
+		t.Fatalf("Expected one item per employee, got %+v", items)
+	}
+
+	items[1].Status = StatusSubmitted
+	items[1].Responses = []ergani.SubmissionResponse{{ID: "1", Protocol: "proto", SubmissionDate: at}}
+	if err := store.Update(ctx, items[1]); err != nil {
+		t.Fatalf("Failed to update: %v", err)
+	}
+	store.Close()
+
+	// Simulate a crash in the middle of a write.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	f.WriteString(`{"id":3,"kind":"over`)
+	f.Close()
+
+	store, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	defer store.Close()
+
+	got, err := store.Get(ctx, items[1].ID)
+	if err != nil {
+		t.Fatalf("Failed to get item: %v", err)
+	}
+	if got.Status != StatusSubmitted || got.Responses[0].Protocol != "proto" || !got.Responses[0].SubmissionDate.Equal(at) {
+		t.Errorf("Expected the latest record to win, got %+v", got)
+	}
+
+	item := Item{Kind: KindOvertime, Status: StatusPending}
+	if err := store.Add(ctx, &item); err != nil {
+		t.Fatalf("Failed to add after repair: %v", err)
+	}
+	if item.ID != 3 {
+		t.Errorf("Expected ID 3, got %d", item.ID)
+	}
+
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	if _, err := store.Get(ctx, items[1].ID); err != ErrNotFound {
+		t.Errorf("Expected submitted item to be compacted away, got %v", err)
+	}
+	pending, _ := store.List(ctx, StatusPending)
+	if len(pending) != 2 || pending[0].ID != 1 || pending[1].ID != 3 {
+		t.Errorf("Expected pending items 1 and 3, got %+v", pending)
+	}
+}
+
+func TestFileStoreCompact_RenameFails(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "outbox.log")
+
+	store, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+	if err := store.Add(ctx, &Item{Kind: KindWorkCard, Status: StatusPending}); err != nil {
+		t.Fatalf("Failed to add item: %v", err)
+	}
+
+	// A non-empty directory in place of the log makes the rename fail.
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove log: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "blocker"), 0o700); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := store.Compact(); err == nil {
+		t.Fatal("Expected the rename to fail")
+	}
+
+	if err := store.Add(ctx, &Item{Kind: KindWorkCard, Status: StatusPending}); err != nil {
+		t.Errorf("Expected the store to keep working after a failed compaction, got %v", err)
+	}
+	if item, err := store.Get(ctx, 1); err != nil || item.Status != StatusPending {
+		t.Errorf("Expected the items to be kept, got %+v, %v", item, err)
+	}
+}
@@ -0,0 +1,166 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Dialect selects the SQL syntax of the database behind a SQLStore.
+type Dialect int
+
+const (
+	SQLite Dialect = iota
+	MySQL
+	PostgreSQL
+)
+
+// SQLStore is a Store backed by a database/sql table. Each item is stored as a
+// JSON document next to its ID and status, which keeps the schema portable across
+// SQL databases. IDs are allocated by the database, so several processes can add
+// items to the same table.
+type SQLStore struct {
+	db      *sql.DB
+	table   string
+	dialect Dialect
+}
+
+// NewSQLStore creates a SQLStore using the given table.
+func NewSQLStore(db *sql.DB, table string, dialect Dialect) *SQLStore {
+	return &SQLStore{db: db, table: table, dialect: dialect}
+}
+
+// placeholder returns the bind parameter for the n-th (1-based) argument.
+func (s *SQLStore) placeholder(n int) string {
+	if s.dialect == PostgreSQL {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// CreateTable creates the outbox table if it does not exist.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	var id string
+	switch s.dialect {
+	case MySQL:
+		id = "id BIGINT AUTO_INCREMENT PRIMARY KEY"
+	case PostgreSQL:
+		id = "id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY"
+	default:
+		id = "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	%s,
+	status VARCHAR(16) NOT NULL,
+	data TEXT NOT NULL
+)`, s.table, id)
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+	return nil
+}
+
+// Add implements Store. The ID is assigned by the identity column of the table and
+// read back with RETURNING on PostgreSQL and LastInsertId otherwise.
+func (s *SQLStore) Add(ctx context.Context, item *Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox item: %w", err)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (status, data) VALUES (%s, %s)", s.table, s.placeholder(1), s.placeholder(2))
+	if s.dialect == PostgreSQL {
+		if err := s.db.QueryRowContext(ctx, query+" RETURNING id", string(item.Status), string(data)).Scan(&item.ID); err != nil {
+			return fmt.Errorf("failed to insert outbox item: %w", err)
+		}
+		return nil
+	}
+
+	result, err := s.db.ExecContext(ctx, query, string(item.Status), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox item: %w", err)
+	}
+	if item.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to read outbox item ID: %w", err)
+	}
+	return nil
+}
+
+// Update implements Store.
+func (s *SQLStore) Update(ctx context.Context, item Item) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox item: %w", err)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = %s, data = %s WHERE id = %s",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	result, err := s.db.ExecContext(ctx, query, string(item.Status), string(data), item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox item: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update outbox item: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s *SQLStore) Get(ctx context.Context, id int64) (Item, error) {
+	query := fmt.Sprintf("SELECT data FROM %s WHERE id = %s", s.table, s.placeholder(1))
+
+	var data string
+	err := s.db.QueryRowContext(ctx, query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Item{}, ErrNotFound
+	}
+	if err != nil {
+		return Item{}, fmt.Errorf("failed to read outbox item: %w", err)
+	}
+
+	var item Item
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		return Item{}, fmt.Errorf("failed to decode outbox item: %w", err)
+	}
+	// The stored document predates the ID assigned by the database.
+	item.ID = id
+	return item, nil
+}
+
+// List implements Store.
+func (s *SQLStore) List(ctx context.Context, status Status) ([]Item, error) {
+	query := fmt.Sprintf("SELECT id, data FROM %s WHERE status = %s ORDER BY id", s.table, s.placeholder(1))
+	rows, err := s.db.QueryContext(ctx, query, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var (
+			id   int64
+			data string
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to read outbox item: %w", err)
+		}
+		var item Item
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox item: %w", err)
+		}
+		item.ID = id
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list outbox items: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,163 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is a database/sql driver that understands the queries of SQLStore and
+// keeps the rows in memory, with an identity column like a real database.
+type fakeDB struct {
+	mu     sync.Mutex
+	nextID int64
+	rows   map[int64][2]string // id -> status, data
+}
+
+func (d *fakeDB) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions are not supported")
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE"):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(s.query, "INSERT"):
+		return fakeResult(s.db.insert(args)), nil
+	case strings.HasPrefix(s.query, "UPDATE"):
+		id := args[2].(int64)
+		if _, ok := s.db.rows[id]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		s.db.rows[id] = [2]string{args[0].(string), args[1].(string)}
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("unexpected statement %q", s.query)
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "INSERT") && strings.HasSuffix(s.query, "RETURNING id"):
+		return &fakeRows{columns: []string{"id"}, values: [][]driver.Value{{s.db.insert(args)}}}, nil
+	case strings.HasPrefix(s.query, "SELECT id, data"):
+		rows := &fakeRows{columns: []string{"id", "data"}}
+		for id := int64(1); id <= s.db.nextID; id++ {
+			if row, ok := s.db.rows[id]; ok && row[0] == args[0].(string) {
+				rows.values = append(rows.values, []driver.Value{id, row[1]})
+			}
+		}
+		return rows, nil
+	case strings.HasPrefix(s.query, "SELECT data"):
+		rows := &fakeRows{columns: []string{"data"}}
+		if row, ok := s.db.rows[args[0].(int64)]; ok {
+			rows.values = append(rows.values, []driver.Value{row[1]})
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", s.query)
+}
+
+func (d *fakeDB) insert(args []driver.Value) int64 {
+	d.nextID++
+	d.rows[d.nextID] = [2]string{args[0].(string), args[1].(string)}
+	return d.nextID
+}
+
+type fakeResult int64
+
+func (r fakeResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestSQLStore(t *testing.T) {
+	for _, dialect := range []Dialect{SQLite, PostgreSQL} {
+		name := fmt.Sprintf("outbox-%d", dialect)
+		sql.Register(name, &fakeDB{rows: make(map[int64][2]string)})
+		db, err := sql.Open(name, "")
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+
+		ctx := context.Background()
+		store := NewSQLStore(db, "ergani_outbox", dialect)
+		if err := store.CreateTable(ctx); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		items := make([]Item, 10)
+		for i := range items {
+			wg.Add(1)
+			go func(item *Item) {
+				defer wg.Done()
+				item.Kind, item.Status = KindWorkCard, StatusPending
+				if err := store.Add(ctx, item); err != nil {
+					t.Errorf("Failed to add item: %v", err)
+				}
+			}(&items[i])
+		}
+		wg.Wait()
+
+		seen := make(map[int64]bool)
+		for _, item := range items {
+			if item.ID == 0 || seen[item.ID] {
+				t.Errorf("Dialect %d: expected distinct IDs from the database, got %d", dialect, item.ID)
+			}
+			seen[item.ID] = true
+		}
+
+		items[3].Status = StatusSubmitted
+		if err := store.Update(ctx, items[3]); err != nil {
+			t.Fatalf("Failed to update item: %v", err)
+		}
+		got, err := store.Get(ctx, items[3].ID)
+		if err != nil || got.ID != items[3].ID || got.Status != StatusSubmitted {
+			t.Errorf("Dialect %d: expected the stored item with its ID, got %+v, %v", dialect, got, err)
+		}
+		pending, err := store.List(ctx, StatusPending)
+		if err != nil || len(pending) != 9 || pending[0].ID != 1 {
+			t.Errorf("Dialect %d: expected 9 pending items in ID order, got %d, %v", dialect, len(pending), err)
+		}
+	}
+}
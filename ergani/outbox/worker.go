@@ -0,0 +1,254 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+const (
+	// DefaultPollInterval is how often Run drains the outbox.
+	DefaultPollInterval = 30 * time.Second
+	// maxBackoff caps the delay between attempts of DefaultBackoff.
+	maxBackoff = 15 * time.Minute
+)
+
+// Submitter sends documents to Ergani. It is implemented by *ergani.Client.
+type Submitter interface {
+	SubmitWorkCard(ctx context.Context, companyWorkCards []ergani.CompanyWorkCard) ([]ergani.SubmissionResponse, error)
+	SubmitOvertime(ctx context.Context, companyOvertimes []ergani.CompanyOvertime) ([]ergani.SubmissionResponse, error)
+	SubmitDailySchedule(ctx context.Context, companyDailySchedules []ergani.CompanyDailySchedule) ([]ergani.SubmissionResponse, error)
+	SubmitWeeklySchedule(ctx context.Context, companyWeeklySchedules []ergani.CompanyWeeklySchedule) ([]ergani.SubmissionResponse, error)
+}
+
+// WorkerConfig configures a Worker.
+type WorkerConfig struct {
+	// MaxAttempts marks an item as failed after this many unsuccessful attempts.
+	// Zero retries until Ergani accepts or rejects the item.
+	MaxAttempts int
+	// Backoff returns the delay before the given (1-based) retry. Defaults to
+	// DefaultBackoff.
+	Backoff func(attempt int) time.Duration
+	// PollInterval is how often Run drains the outbox. Defaults to DefaultPollInterval.
+	PollInterval time.Duration
+}
+
+// Worker drains an outbox through a Submitter.
+type Worker struct {
+	store        Store
+	submitter    Submitter
+	maxAttempts  int
+	backoff      func(attempt int) time.Duration
+	pollInterval time.Duration
+	// now returns the current time and is replaced in tests.
+	now func() time.Time
+}
+
+// NewWorker creates a Worker that submits the pending items of store.
+func NewWorker(store Store, submitter Submitter, config WorkerConfig) *Worker {
+	backoff := config.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+
+	pollInterval := config.PollInterval
+	if pollInterval == 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	return &Worker{
+		store:        store,
+		submitter:    submitter,
+		maxAttempts:  config.MaxAttempts,
+		backoff:      backoff,
+		pollInterval: pollInterval,
+		now:          time.Now,
+	}
+}
+
+// DefaultBackoff doubles the delay on every attempt, starting at 5 seconds and
+// capped at 15 minutes.
+func DefaultBackoff(attempt int) time.Duration {
+	d := 5 * time.Second
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+// Run drains the outbox every PollInterval until ctx is cancelled. Store errors
+// stop the worker.
+func (w *Worker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Drain(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain makes one pass over the pending items in the order they were enqueued.
+// An item that is waiting for a retry or has failed holds back the later items of
+// the same employees, so that e.g. a departure is never sent after its arrival was
+// rejected. A failed item keeps holding them back until it is updated to
+// StatusPending, e.g. with a corrected payload, or to StatusSubmitted once it has
+// been resolved by hand. Submission errors are recorded on the items; only store
+// errors are returned.
+//
+// Work cards are submitted with ergani.WithLateJustification, so that entries that
+// turn out to be late are justified with ErganiSystemsUnavailable when the last
+// attempt failed because Ergani was unavailable and with EmployerSystemsUnavailable
+// otherwise, e.g. after a network failure on the employer's side.
+func (w *Worker) Drain(ctx context.Context) error {
+	items, err := w.store.List(ctx, StatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to list pending items: %w", err)
+	}
+
+	failed, err := w.store.List(ctx, StatusFailed)
+	if err != nil {
+		return fmt.Errorf("failed to list failed items: %w", err)
+	}
+
+	blocked := make(map[string]bool)
+	for _, item := range failed {
+		block(item, blocked)
+	}
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if isBlocked(item, blocked) || w.now().Before(item.NextAttempt) {
+			block(item, blocked)
+			continue
+		}
+
+		responses, err := w.submit(ctx, item)
+		if ctx.Err() != nil {
+			// The attempt was interrupted, not rejected.
+			return nil
+		}
+
+		item.Attempts++
+		item.UpdatedAt = w.now()
+		item.ErganiUnavailable = erganiUnavailable(err)
+		switch {
+		case err == nil, errors.Is(err, ergani.ErrNotRecorded):
+			// Ergani accepted the item even if the client could not record it.
+			item.Status = StatusSubmitted
+			item.Responses = responses
+			item.LastError = ""
+		case !Retryable(err) || (w.maxAttempts > 0 && item.Attempts >= w.maxAttempts):
+			item.Status = StatusFailed
+			item.LastError = err.Error()
+			block(item, blocked)
+		default:
+			item.LastError = err.Error()
+			item.NextAttempt = item.UpdatedAt.Add(w.backoff(item.Attempts))
+			block(item, blocked)
+		}
+
+		if err := w.store.Update(ctx, item); err != nil {
+			return fmt.Errorf("failed to update item %d: %w", item.ID, err)
+		}
+	}
+	return nil
+}
+
+// Retryable reports whether a submission error is transient. Validation errors and
+// API rejections are permanent; network failures, server errors, rate limiting and
+// authentication failures, e.g. while the credentials are being rotated, are retried.
+func Retryable(err error) bool {
+	var validationErr *ergani.ValidationError
+	if errors.As(err, &validationErr) {
+		return false
+	}
+
+	var apiErr *ergani.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError ||
+			apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == http.StatusUnauthorized
+	}
+	return true
+}
+
+// erganiUnavailable reports whether err is a server error or rate limiting
+// response of Ergani.
+func erganiUnavailable(err error) bool {
+	var apiErr *ergani.APIError
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests)
+}
+
+func (w *Worker) submit(ctx context.Context, item Item) ([]ergani.SubmissionResponse, error) {
+	switch item.Kind {
+	case KindWorkCard:
+		var doc ergani.CompanyWorkCard
+		if err := json.Unmarshal(item.Payload, &doc); err != nil {
+			return nil, &ergani.ValidationError{Field: "Payload", Message: err.Error()}
+		}
+		return w.submitter.SubmitWorkCard(ergani.WithLateJustification(ctx, lateJustification(item)), []ergani.CompanyWorkCard{doc})
+	case KindOvertime:
+		var doc ergani.CompanyOvertime
+		if err := json.Unmarshal(item.Payload, &doc); err != nil {
+			return nil, &ergani.ValidationError{Field: "Payload", Message: err.Error()}
+		}
+		return w.submitter.SubmitOvertime(ctx, []ergani.CompanyOvertime{doc})
+	case KindDailySchedule:
+		var doc ergani.CompanyDailySchedule
+		if err := json.Unmarshal(item.Payload, &doc); err != nil {
+			return nil, &ergani.ValidationError{Field: "Payload", Message: err.Error()}
+		}
+		return w.submitter.SubmitDailySchedule(ctx, []ergani.CompanyDailySchedule{doc})
+	case KindWeeklySchedule:
+		var doc ergani.CompanyWeeklySchedule
+		if err := json.Unmarshal(item.Payload, &doc); err != nil {
+			return nil, &ergani.ValidationError{Field: "Payload", Message: err.Error()}
+		}
+		return w.submitter.SubmitWeeklySchedule(ctx, []ergani.CompanyWeeklySchedule{doc})
+	default:
+		return nil, &ergani.ValidationError{Field: "Kind", Message: fmt.Sprintf("unknown outbox item kind %q", item.Kind)}
+	}
+}
+
+// lateJustification returns the reason for replaying a work card late: entries
+// whose last attempt failed because Ergani was unavailable were delayed by Ergani,
+// all others by the employer's systems.
+func lateJustification(item Item) ergani.LateDeclarationJustificationType {
+	if item.ErganiUnavailable {
+		return ergani.ErganiSystemsUnavailable
+	}
+	return ergani.EmployerSystemsUnavailable
+}
+
+func isBlocked(item Item, blocked map[string]bool) bool {
+	for _, key := range item.Keys {
+		if blocked[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func block(item Item, blocked map[string]bool) {
+	for _, key := range item.Keys {
+		blocked[key] = true
+	}
+}
@@ -0,0 +1,228 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+type fakeSubmitter struct {
+	errs []error
+	sent []ergani.WorkCard
+}
+
+func (f *fakeSubmitter) SubmitWorkCard(_ context.Context, cwcs []ergani.CompanyWorkCard) ([]ergani.SubmissionResponse, error) {
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	f.sent = append(f.sent, cwcs[0].CardDetails...)
+	return []ergani.SubmissionResponse{{ID: "1", Protocol: "proto"}}, nil
+}
+
+func (f *fakeSubmitter) SubmitOvertime(context.Context, []ergani.CompanyOvertime) ([]ergani.SubmissionResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeSubmitter) SubmitDailySchedule(context.Context, []ergani.CompanyDailySchedule) ([]ergani.SubmissionResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeSubmitter) SubmitWeeklySchedule(context.Context, []ergani.CompanyWeeklySchedule) ([]ergani.SubmissionResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestWorkerDrain(t *testing.T) {
+	ctx := context.Background()
+	store, err := OpenFileStore(filepath.Join(t.TempDir(), "outbox.log"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now().Add(time.Hour)
+	at := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	enqueue := func(cards ...ergani.WorkCard) {
+		if _, err := New(store).EnqueueWorkCard(ctx, ergani.CompanyWorkCard{EmployerTaxID: "999999999", CardDetails: cards}); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+	}
+	enqueue(testWorkCard("111111111", ergani.Arrival, at), testWorkCard("222222222", ergani.Arrival, at))
+	enqueue(testWorkCard("111111111", ergani.Departure, at.Add(8*time.Hour)))
+	enqueue(testWorkCard("333333333", ergani.Arrival, at))
+
+	submitter := &fakeSubmitter{errs: []error{
+		&ergani.APIError{StatusCode: 503, Message: "unavailable"},
+		nil,
+		&ergani.APIError{StatusCode: 400, Message: "invalid tax ID"},
+	}}
+	worker := NewWorker(store, submitter, WorkerConfig{Backoff: func(int) time.Duration { return time.Minute }})
+	worker.now = func() time.Time { return now }
+
+	if err := worker.Drain(ctx); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+
+	// The first arrival of 111111111 is retried later and holds back its departure.
+	if len(submitter.sent) != 1 || submitter.sent[0].EmployeeTaxID != "222222222" {
+		t.Fatalf("Expected only 222222222 to be sent, got %+v", submitter.sent)
+	}
+	first, _ := store.Get(ctx, 1)
+	if first.Status != StatusPending || first.Attempts != 1 || !first.NextAttempt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected item 1 to be scheduled for retry, got %+v", first)
+	}
+	rejected, _ := store.Get(ctx, 4)
+	if rejected.Status != StatusFailed || rejected.LastError == "" {
+		t.Errorf("Expected item 4 to fail permanently, got %+v", rejected)
+	}
+
+	// Not yet due.
+	if err := worker.Drain(ctx); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(submitter.sent) != 1 {
+		t.Fatalf("Expected no submissions before the retry is due, got %+v", submitter.sent)
+	}
+
+	now = now.Add(time.Minute)
+	if err := worker.Drain(ctx); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(submitter.sent) != 3 ||
+		submitter.sent[1].WorkCardMovementType != ergani.Arrival ||
+		submitter.sent[2].WorkCardMovementType != ergani.Departure {
+		t.Fatalf("Expected arrival then departure of 111111111, got %+v", submitter.sent)
+	}
+	pending, _ := store.List(ctx, StatusPending)
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending items, got %+v", pending)
+	}
+}
+
+func TestWorkerDrain_FailedItemBlocks(t *testing.T) {
+	ctx := context.Background()
+	store, err := OpenFileStore(filepath.Join(t.TempDir(), "outbox.log"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer store.Close()
+
+	at := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	for _, card := range []ergani.WorkCard{
+		testWorkCard("111111111", ergani.Arrival, at),
+		testWorkCard("111111111", ergani.Departure, at.Add(8*time.Hour)),
+		testWorkCard("222222222", ergani.Arrival, at),
+	} {
+		if _, err := New(store).EnqueueWorkCard(ctx, ergani.CompanyWorkCard{EmployerTaxID: "999999999", CardDetails: []ergani.WorkCard{card}}); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+	}
+
+	submitter := &fakeSubmitter{errs: []error{&ergani.APIError{StatusCode: 400, Message: "invalid arrival"}}}
+	worker := NewWorker(store, submitter, WorkerConfig{})
+
+	// The rejected arrival holds back the departure, in this pass and the next.
+	for pass := 0; pass < 2; pass++ {
+		if err := worker.Drain(ctx); err != nil {
+			t.Fatalf("Drain failed: %v", err)
+		}
+		if len(submitter.sent) != 1 || submitter.sent[0].EmployeeTaxID != "222222222" {
+			t.Fatalf("Pass %d: expected only 222222222 to be sent, got %+v", pass, submitter.sent)
+		}
+	}
+	departure, _ := store.Get(ctx, 2)
+	if departure.Status != StatusPending || departure.Attempts != 0 {
+		t.Errorf("Expected the departure to wait, got %+v", departure)
+	}
+
+	// Requeueing the corrected arrival releases the departure.
+	arrival, _ := store.Get(ctx, 1)
+	arrival.Status = StatusPending
+	if err := store.Update(ctx, arrival); err != nil {
+		t.Fatalf("Failed to requeue: %v", err)
+	}
+	if err := worker.Drain(ctx); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if len(submitter.sent) != 3 ||
+		submitter.sent[1].WorkCardMovementType != ergani.Arrival ||
+		submitter.sent[2].WorkCardMovementType != ergani.Departure {
+		t.Fatalf("Expected arrival then departure of 111111111, got %+v", submitter.sent)
+	}
+}
+
+func TestWorkerDrain_LateJustification(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want ergani.LateDeclarationJustificationType
+	}{
+		{"server error", &ergani.APIError{StatusCode: 503, Message: "unavailable"}, ergani.ErganiSystemsUnavailable},
+		{"rate limited", &ergani.APIError{StatusCode: 429, Message: "slow down"}, ergani.ErganiSystemsUnavailable},
+		{"network failure", errors.New("dial tcp: connection refused"), ergani.EmployerSystemsUnavailable},
+	}
+	for _, c := range cases {
+		ctx := context.Background()
+		store, err := OpenFileStore(filepath.Join(t.TempDir(), "outbox.log"))
+		if err != nil {
+			t.Fatalf("Failed to open store: %v", err)
+		}
+		at := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+		if _, err := New(store).EnqueueWorkCard(ctx, ergani.CompanyWorkCard{EmployerTaxID: "999999999", CardDetails: []ergani.WorkCard{testWorkCard("111111111", ergani.Arrival, at)}}); err != nil {
+			t.Fatalf("Failed to enqueue: %v", err)
+		}
+
+		item, _ := store.Get(ctx, 1)
+		if got := lateJustification(item); got != ergani.EmployerSystemsUnavailable {
+			t.Errorf("%s: expected %s before any attempt, got %s", c.name, ergani.EmployerSystemsUnavailable, got)
+		}
+
+		worker := NewWorker(store, &fakeSubmitter{errs: []error{c.err}}, WorkerConfig{})
+		if err := worker.Drain(ctx); err != nil {
+			t.Fatalf("Drain failed: %v", err)
+		}
+		item, _ = store.Get(ctx, 1)
+		if got := lateJustification(item); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+		store.Close()
+	}
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	if d := DefaultBackoff(1); d != 5*time.Second {
+		t.Errorf("Expected 5s, got %v", d)
+	}
+	if d := DefaultBackoff(3); d != 20*time.Second {
+		t.Errorf("Expected 20s, got %v", d)
+	}
+	if d := DefaultBackoff(100); d != maxBackoff {
+		t.Errorf("Expected %v, got %v", maxBackoff, d)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{&ergani.ValidationError{Field: "f", Message: "m"}, false},
+		{&ergani.APIError{StatusCode: 400}, false},
+		{&ergani.APIError{StatusCode: 401}, true},
+		{&ergani.APIError{StatusCode: 429}, true},
+		{&ergani.APIError{StatusCode: 503}, true},
+		{errors.New("connection refused"), true},
+	}
+	for _, c := range cases {
+		if got := Retryable(c.err); got != c.want {
+			t.Errorf("Expected Retryable(%v) to be %v, got %v", c.err, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Package overtime derives overtime from declared work time schedules and actual
+// or planned work, and generates the Overtime records to submit for it.
+package overtime
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/shifts"
+)
+
+// Kind classifies an overtime interval relative to the declared schedule.
+type Kind string
+
+const (
+	// BeforeSchedule is work before the declared start of the day.
+	BeforeSchedule Kind = "BEFORE_SCHEDULE"
+	// AfterSchedule is work after the declared end of a working period.
+	AfterSchedule Kind = "AFTER_SCHEDULE"
+	// NonWorkingDay is work on a day without declared working periods, e.g. a
+	// RestDay or an undeclared day.
+	NonWorkingDay Kind = "NON_WORKING_DAY"
+)
+
+// Work is a period an employee worked or is planned to work.
+type Work struct {
+	EmployeeTaxID string
+	Start         time.Time
+	End           time.Time
+}
+
+// FromShifts converts reconstructed shifts to work periods.
+func FromShifts(ss []shifts.Shift) []Work {
+	work := make([]Work, len(ss))
+	for i, s := range ss {
+		work[i] = Work{EmployeeTaxID: s.EmployeeTaxID, Start: s.Start(), End: s.End()}
+	}
+	return work
+}
+
+// Interval is a period of work outside the declared schedule.
+type Interval struct {
+	EmployeeTaxID string
+	// Date is the day the interval starts on.
+	Date  ergani.Date
+	Start time.Time
+	End   time.Time
+	Kind  Kind
+}
+
+// Duration returns the length of the interval.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Employee holds the details an Overtime record needs about an employee.
+type Employee struct {
+	TaxID          string
+	SSN            string
+	LastName       string
+	FirstName      string
+	ProfessionCode string
+}
+
+// Config configures a Calculator.
+type Config struct {
+	// Schedules holds the declared daily and weekly schedules.
+	Schedules *ergani.ScheduleIndex
+	// MinDuration drops intervals shorter than this.
+	MinDuration time.Duration
+	// Location is used to resolve dates. Defaults to time.Local.
+	Location *time.Location
+	// Justifications maps an interval kind to the justification of its record.
+	Justifications map[Kind]ergani.OvertimeJustificationType
+	// DefaultJustification is used for kinds missing from Justifications. Defaults
+	// to ExceptionalWorkload.
+	DefaultJustification ergani.OvertimeJustificationType
+	// Justify, if set, overrides the justification of an interval when it returns true.
+	Justify func(Interval) (ergani.OvertimeJustificationType, bool)
+}
+
+// Calculator derives overtime intervals and records.
+type Calculator struct {
+	config Config
+}
+
+// NewCalculator creates a Calculator.
+func NewCalculator(config Config) *Calculator {
+	if config.Schedules == nil {
+		config.Schedules = ergani.NewScheduleIndex()
+	}
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	if config.DefaultJustification == "" {
+		config.DefaultJustification = ergani.ExceptionalWorkload
+	}
+	return &Calculator{config: config}
+}
+
+// Intervals returns the parts of the work that fall outside the working periods
+// declared for the employee, ordered by employee and start time. Periods declared
+// on the previous or next day are taken into account for work around midnight.
+func (c *Calculator) Intervals(work []Work) []Interval {
+	var intervals []Interval
+	for _, w := range work {
+		start, end := w.Start.In(c.config.Location), w.End.In(c.config.Location)
+		date := startOfDay(start)
+
+		var periods []period
+		for _, d := range []time.Time{date.AddDate(0, 0, -1), date, date.AddDate(0, 0, 1)} {
+			if day, ok := c.config.Schedules.Lookup(w.EmployeeTaxID, d); ok {
+				periods = append(periods, workPeriods(day, d)...)
+			}
+		}
+		sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+		var overlapping []period
+		for _, p := range periods {
+			if p.start.Before(end) && p.end.After(start) {
+				overlapping = append(overlapping, p)
+			}
+		}
+
+		for _, seg := range subtract(start, end, periods) {
+			interval := Interval{
+				EmployeeTaxID: w.EmployeeTaxID,
+				Date:          ergani.Date{Time: startOfDay(seg.start)},
+				Start:         seg.start,
+				End:           seg.end,
+			}
+			// Without overlapping periods the interval is classified against the
+			// periods of its own day.
+			ref := overlapping
+			if len(ref) == 0 {
+				for _, p := range periods {
+					if startOfDay(p.start).Equal(interval.Date.Time) {
+						ref = append(ref, p)
+					}
+				}
+			}
+			switch {
+			case len(overlapping) == 0 && !c.isWorkDay(w.EmployeeTaxID, interval.Date.Time):
+				interval.Kind = NonWorkingDay
+			case len(ref) > 0 && !seg.end.After(ref[0].start):
+				interval.Kind = BeforeSchedule
+			default:
+				interval.Kind = AfterSchedule
+			}
+			if interval.Duration() >= c.config.MinDuration && interval.Duration() > 0 {
+				intervals = append(intervals, interval)
+			}
+		}
+	}
+
+	sort.SliceStable(intervals, func(i, j int) bool {
+		if intervals[i].EmployeeTaxID != intervals[j].EmployeeTaxID {
+			return intervals[i].EmployeeTaxID < intervals[j].EmployeeTaxID
+		}
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+	return intervals
+}
+
+// WeeklyWorkdays returns the WeeklyWorkdaysNumber of an employee for the week of
+// date: 6 if at least six working days are declared in that week and 5 otherwise.
+func (c *Calculator) WeeklyWorkdays(taxID string, date time.Time) int {
+	day := startOfDay(date.In(c.config.Location))
+	monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+
+	workdays := 0
+	for i := 0; i < 7; i++ {
+		if c.isWorkDay(taxID, monday.AddDate(0, 0, i)) {
+			workdays++
+		}
+	}
+	if workdays >= 6 {
+		return 6
+	}
+	return 5
+}
+
+// Justification returns the justification for an interval.
+func (c *Calculator) Justification(interval Interval) ergani.OvertimeJustificationType {
+	if c.config.Justify != nil {
+		if j, ok := c.config.Justify(interval); ok {
+			return j
+		}
+	}
+	if j, ok := c.config.Justifications[interval.Kind]; ok {
+		return j
+	}
+	return c.config.DefaultJustification
+}
+
+// Overtime returns an Overtime record for every interval. Every employee of the
+// intervals must be present in employees.
+func (c *Calculator) Overtime(employees map[string]Employee, intervals []Interval) ([]ergani.Overtime, error) {
+	records := make([]ergani.Overtime, 0, len(intervals))
+	for _, interval := range intervals {
+		employee, ok := employees[interval.EmployeeTaxID]
+		if !ok {
+			return nil, &ergani.ValidationError{
+				Field:   "EmployeeTaxID",
+				Message: fmt.Sprintf("no employee details for %s", interval.EmployeeTaxID),
+			}
+		}
+
+		records = append(records, ergani.Overtime{
+			EmployeeTaxID:          employee.TaxID,
+			EmployeeSSN:            employee.SSN,
+			EmployeeLastName:       employee.LastName,
+			EmployeeFirstName:      employee.FirstName,
+			OvertimeDate:           interval.Date,
+			OvertimeStartTime:      ergani.Time{Time: interval.Start},
+			OvertimeEndTime:        ergani.Time{Time: interval.End},
+			EmployeeProfessionCode: employee.ProfessionCode,
+			OvertimeJustification:  c.Justification(interval),
+			WeeklyWorkdaysNumber:   c.WeeklyWorkdays(interval.EmployeeTaxID, interval.Date.Time),
+		})
+	}
+	return records, nil
+}
+
+// CompanyOvertime computes the overtime of the work and returns it as a document
+// with the given header, e.g. from ergani.Employer.OvertimeHeader.
+func (c *Calculator) CompanyOvertime(header ergani.CompanyOvertime, employees map[string]Employee, work []Work) (ergani.CompanyOvertime, error) {
+	records, err := c.Overtime(employees, c.Intervals(work))
+	if err != nil {
+		return ergani.CompanyOvertime{}, err
+	}
+	header.EmployeeOvertimes = records
+	return header, nil
+}
+
+func (c *Calculator) isWorkDay(taxID string, date time.Time) bool {
+	day, ok := c.config.Schedules.Lookup(taxID, date)
+	return ok && day.IsWork()
+}
+
+type period struct {
+	start, end time.Time
+}
+
+func workPeriods(day ergani.ScheduledDay, date time.Time) []period {
+	var periods []period
+	for _, wd := range day.WorkdayDetails {
+		if wd.WorkType.IsWork() {
+			start, end := wd.On(date)
+			periods = append(periods, period{start, end})
+		}
+	}
+	return periods
+}
+
+// subtract returns the parts of [start, end) not covered by the sorted periods.
+func subtract(start, end time.Time, periods []period) []period {
+	var rest []period
+	cur := start
+	for _, p := range periods {
+		if !p.end.After(cur) || !p.start.Before(end) {
+			continue
+		}
+		if p.start.After(cur) {
+			rest = append(rest, period{cur, p.start})
+		}
+		cur = p.end
+		if !cur.Before(end) {
+			return rest
+		}
+	}
+	return append(rest, period{cur, end})
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,86 @@
+package overtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+func TestCalculator(t *testing.T) {
+	monday := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	at := func(day, h, m int) time.Time {
+		return monday.AddDate(0, 0, day).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	weekly := ergani.CompanyWeeklySchedule{
+		StartDate: ergani.Date{Time: monday},
+		EndDate:   ergani.Date{Time: monday.AddDate(0, 0, 6)},
+	}
+	for d := time.Monday; d <= time.Saturday; d++ {
+		weekly.EmployeeSchedules = append(weekly.EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+			EmployeeTaxID:  "111111111",
+			ScheduleDay:    ergani.Weekday{Weekday: d},
+			WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00"), testutil.Workday(t, ergani.WorkFromOffice, "17:00", "20:00")},
+		})
+	}
+	weekly.EmployeeSchedules = append(weekly.EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+		EmployeeTaxID:  "111111111",
+		ScheduleDay:    ergani.Weekday{Weekday: time.Sunday},
+		WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.RestDay, "00:00", "00:00")},
+	})
+	index := ergani.NewScheduleIndex()
+	index.AddWeekly(weekly)
+
+	calc := NewCalculator(Config{
+		Schedules:      index,
+		Location:       time.UTC,
+		MinDuration:    15 * time.Minute,
+		Justifications: map[Kind]ergani.OvertimeJustificationType{NonWorkingDay: ergani.NonWorkdayTasks},
+	})
+
+	intervals := calc.Intervals([]Work{
+		// Early start, through the split break and late end.
+		{EmployeeTaxID: "111111111", Start: at(0, 8, 0), End: at(0, 22, 0)},
+		// Ten minutes late, below the minimum.
+		{EmployeeTaxID: "111111111", Start: at(1, 9, 0), End: at(1, 13, 10)},
+		// Sunday rest day.
+		{EmployeeTaxID: "111111111", Start: at(6, 10, 0), End: at(6, 14, 0)},
+	})
+
+	want := []struct {
+		kind       Kind
+		start, end time.Time
+	}{
+		{BeforeSchedule, at(0, 8, 0), at(0, 9, 0)},
+		{AfterSchedule, at(0, 13, 0), at(0, 17, 0)},
+		{AfterSchedule, at(0, 20, 0), at(0, 22, 0)},
+		{NonWorkingDay, at(6, 10, 0), at(6, 14, 0)},
+	}
+	if len(intervals) != len(want) {
+		t.Fatalf("Expected %d intervals, got %+v", len(want), intervals)
+	}
+	for i, w := range want {
+		if intervals[i].Kind != w.kind || !intervals[i].Start.Equal(w.start) || !intervals[i].End.Equal(w.end) {
+			t.Errorf("Interval %d: expected %s %v-%v, got %+v", i, w.kind, w.start, w.end, intervals[i])
+		}
+	}
+
+	employees := map[string]Employee{"111111111": {TaxID: "111111111", SSN: "01017012345", LastName: "Doe", FirstName: "John", ProfessionCode: "913230"}}
+	doc, err := calc.CompanyOvertime(ergani.CompanyOvertime{BusinessBranchNumber: 1}, employees, []Work{
+		{EmployeeTaxID: "111111111", Start: at(6, 10, 0), End: at(6, 14, 0)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ot := doc.EmployeeOvertimes[0]
+	if doc.BusinessBranchNumber != 1 || ot.OvertimeStartTime.Format("15:04") != "10:00" || ot.OvertimeEndTime.Format("15:04") != "14:00" ||
+		ot.WeeklyWorkdaysNumber != 6 || ot.OvertimeJustification != ergani.NonWorkdayTasks || ot.EmployeeSSN != "01017012345" {
+		t.Errorf("Unexpected overtime record %+v", ot)
+	}
+
+	if _, err := calc.Overtime(map[string]Employee{}, intervals); err == nil {
+		t.Error("Expected an error for an unknown employee")
+	}
+}
@@ -0,0 +1,148 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// justifications get an overtime column of their own, in column order. Overtime
+// with any other justification is reported as OTHER.
+var justifications = []ergani.OvertimeJustificationType{
+	ergani.AccidentPreventionOrDamageRestoration,
+	ergani.UrgentSeasonalTasks,
+	ergani.ExceptionalWorkload,
+	ergani.SupplementaryTasks,
+	ergani.LostHoursSuddenCauses,
+	ergani.LostHoursOfficialHolidays,
+	ergani.LostHoursWeatherConditions,
+	ergani.EmergencyClosureDay,
+	ergani.NonWorkdayTasks,
+}
+
+// CSVHeader is the column layout written by WriteCSV. Durations are in whole
+// minutes, truncated. regular_minutes is worked_minutes less the truncated part of
+// worked_minutes covered by overtime. The overtime columns hold the declared
+// overtime, which may include hours that were not worked or that overlap other
+// declarations, so they do not add up to worked_minutes; overtime_total_minutes is
+// the sum of the overtime columns.
+var CSVHeader = csvHeader()
+
+func csvHeader() []string {
+	header := []string{
+		"employee_tax_id", "period_from", "period_to",
+		"worked_minutes", "regular_minutes", "night_minutes", "sunday_holiday_minutes",
+	}
+	for _, j := range justifications {
+		header = append(header, overtimeColumn(string(j)))
+	}
+	return append(header, overtimeColumn("OTHER"), "overtime_total_minutes")
+}
+
+func overtimeColumn(justification string) string {
+	return "overtime_" + strings.ToLower(justification) + "_minutes"
+}
+
+// record is the exported form of a Summary.
+type record struct {
+	EmployeeTaxID string         `json:"employee_tax_id"`
+	From          string         `json:"period_from"`
+	To            string         `json:"period_to"`
+	Worked        int            `json:"worked_minutes"`
+	Regular       int            `json:"regular_minutes"`
+	Night         int            `json:"night_minutes"`
+	SundayHoliday int            `json:"sunday_holiday_minutes"`
+	Overtime      map[string]int `json:"overtime_minutes"`
+	OvertimeTotal int            `json:"overtime_total_minutes"`
+}
+
+func newRecord(s Summary) record {
+	r := record{
+		EmployeeTaxID: s.EmployeeTaxID,
+		From:          formatDate(s.From),
+		To:            formatDate(s.To),
+		Worked:        minutes(s.Worked),
+		Regular:       minutes(s.Worked) - minutes(s.Worked-s.Regular),
+		Night:         minutes(s.Night),
+		SundayHoliday: minutes(s.SundayHoliday),
+		Overtime:      make(map[string]int, len(justifications)+1),
+	}
+	for _, j := range justifications {
+		r.Overtime[string(j)] = minutes(s.Overtime[j])
+	}
+	var other time.Duration
+	for j, d := range s.Overtime {
+		if !known(j) {
+			other += d
+		}
+	}
+	r.Overtime["OTHER"] = minutes(other)
+	for _, m := range r.Overtime {
+		r.OvertimeTotal += m
+	}
+	return r
+}
+
+// WriteCSV writes the summaries as CSV with the CSVHeader columns. Dates are
+// formatted as DD/MM/YYYY and empty for an open period.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, s := range r.Summaries {
+		rec := newRecord(s)
+		row := []string{
+			rec.EmployeeTaxID, rec.From, rec.To,
+			strconv.Itoa(rec.Worked), strconv.Itoa(rec.Regular), strconv.Itoa(rec.Night), strconv.Itoa(rec.SundayHoliday),
+		}
+		for _, j := range justifications {
+			row = append(row, strconv.Itoa(rec.Overtime[string(j)]))
+		}
+		row = append(row, strconv.Itoa(rec.Overtime["OTHER"]), strconv.Itoa(rec.OvertimeTotal))
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the summaries as a JSON array. Overtime is an object keyed by
+// justification that always contains every justification and OTHER.
+func (r Report) WriteJSON(w io.Writer) error {
+	records := make([]record, len(r.Summaries))
+	for i, s := range r.Summaries {
+		records[i] = newRecord(s)
+	}
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+func known(j ergani.OvertimeJustificationType) bool {
+	for _, k := range justifications {
+		if j == k {
+			return true
+		}
+	}
+	return false
+}
+
+func minutes(d time.Duration) int {
+	return int(d / time.Minute)
+}
+
+func formatDate(d ergani.Date) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format("02/01/2006")
+}
@@ -0,0 +1,253 @@
+// Package payroll aggregates work card movements and submitted overtime into
+// per-employee totals for payroll systems.
+//
+// Work cards are paired into shifts with the shifts package and clipped to the
+// payroll period. The worked time is split into regular time and the declared
+// overtime per justification, and the night (22:00–06:00) and Sunday or public
+// holiday hours are counted separately. Summaries are exported as CSV or JSON with
+// a fixed column schema.
+package payroll
+
+import (
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/holidays"
+	"github.com/takispanag/ergani-go-sdk/ergani/shifts"
+)
+
+const (
+	// NightStart and NightEnd bound the night hours as offsets from midnight.
+	NightStart = 22 * time.Hour
+	NightEnd   = 6 * time.Hour
+)
+
+// Config configures Aggregate.
+type Config struct {
+	// From and To bound the payroll period, To exclusive. Work and overtime outside
+	// the period are not counted.
+	From time.Time
+	To   time.Time
+	// Location is used to resolve dates, night hours and Sundays. Defaults to
+	// time.Local.
+	Location *time.Location
+	// IsHoliday reports public holidays. Defaults to the national holidays of the
+	// holidays package.
+	IsHoliday func(ergani.Date) bool
+	// Shifts configures how work cards are paired into shifts.
+	Shifts shifts.Options
+}
+
+// Summary holds the payroll totals of an employee for a period.
+type Summary struct {
+	EmployeeTaxID string
+	// From and To are the bounds of the payroll period, To exclusive.
+	From ergani.Date
+	To   ergani.Date
+	// Worked is the time between arrivals and departures within the period.
+	Worked time.Duration
+	// Regular is the worked time not covered by declared overtime. Overtime that
+	// was declared but not worked does not reduce it.
+	Regular time.Duration
+	// Overtime is the declared overtime per justification.
+	Overtime map[ergani.OvertimeJustificationType]time.Duration
+	// Night is the worked time between 22:00 and 06:00.
+	Night time.Duration
+	// SundayHoliday is the worked time on Sundays and public holidays.
+	SundayHoliday time.Duration
+}
+
+// TotalOvertime returns the declared overtime of all justifications.
+func (s Summary) TotalOvertime() time.Duration {
+	var total time.Duration
+	for _, d := range s.Overtime {
+		total += d
+	}
+	return total
+}
+
+// Report is the result of an aggregation.
+type Report struct {
+	// Summaries are ordered by employee tax ID.
+	Summaries []Summary
+	// Findings are the work card movements that could not be paired into shifts
+	// and are therefore not counted.
+	Findings []shifts.Finding
+}
+
+// Aggregate computes the payroll summaries of the employees that worked or have
+// overtime declared in the period. Cancellation rows remove the matching overtime
+// of the same employee, date and times.
+func Aggregate(cards []ergani.WorkCard, overtimes []ergani.CompanyOvertime, cfg Config) Report {
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.IsHoliday == nil {
+		cfg.IsHoliday = func(d ergani.Date) bool {
+			_, ok := holidays.IsHoliday(d)
+			return ok
+		}
+	}
+	if cfg.Shifts.Location == nil {
+		cfg.Shifts.Location = cfg.Location
+	}
+
+	summaries := make(map[string]*Summary)
+	summary := func(taxID string) *Summary {
+		s, ok := summaries[taxID]
+		if !ok {
+			s = &Summary{
+				EmployeeTaxID: taxID,
+				From:          ergani.Date{Time: cfg.From},
+				To:            ergani.Date{Time: cfg.To},
+				Overtime:      make(map[ergani.OvertimeJustificationType]time.Duration),
+			}
+			summaries[taxID] = s
+		}
+		return s
+	}
+
+	worked := make(map[string][]interval)
+	overtime := make(map[string][]interval)
+
+	result := shifts.Reconstruct(cards, cfg.Shifts)
+	for _, shift := range result.Shifts {
+		start, end, ok := clip(shift.Start(), shift.End(), cfg.From, cfg.To)
+		if !ok {
+			continue
+		}
+		worked[shift.EmployeeTaxID] = append(worked[shift.EmployeeTaxID], interval{start, end})
+		s := summary(shift.EmployeeTaxID)
+		s.Worked += end.Sub(start)
+		s.Night += nightHours(start.In(cfg.Location), end.In(cfg.Location))
+		s.SundayHoliday += sundayHolidayHours(start.In(cfg.Location), end.In(cfg.Location), cfg.IsHoliday)
+	}
+
+	for _, ot := range declaredOvertime(overtimes, cfg.Location) {
+		start, end, ok := clip(ot.start, ot.end, cfg.From, cfg.To)
+		if !ok {
+			continue
+		}
+		overtime[ot.taxID] = append(overtime[ot.taxID], interval{start, end})
+		summary(ot.taxID).Overtime[ot.justification] += end.Sub(start)
+	}
+
+	report := Report{Findings: result.Findings}
+	for taxID, s := range summaries {
+		s.Regular = s.Worked - overlap(worked[taxID], overtime[taxID])
+		report.Summaries = append(report.Summaries, *s)
+	}
+	sort.Slice(report.Summaries, func(i, j int) bool {
+		return report.Summaries[i].EmployeeTaxID < report.Summaries[j].EmployeeTaxID
+	})
+	return report
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// overlap returns the time covered by both a and b. The intervals of each list may
+// overlap each other.
+func overlap(a, b []interval) time.Duration {
+	var common []interval
+	for _, x := range a {
+		for _, y := range b {
+			if start, end, ok := clip(x.start, x.end, y.start, y.end); ok {
+				common = append(common, interval{start, end})
+			}
+		}
+	}
+	sort.Slice(common, func(i, j int) bool { return common[i].start.Before(common[j].start) })
+
+	var total time.Duration
+	var covered time.Time
+	for _, c := range common {
+		if c.start.Before(covered) {
+			c.start = covered
+		}
+		if c.start.Before(c.end) {
+			total += c.end.Sub(c.start)
+			covered = c.end
+		}
+	}
+	return total
+}
+
+type overtimePeriod struct {
+	taxID         string
+	start, end    time.Time
+	justification ergani.OvertimeJustificationType
+}
+
+// declaredOvertime returns the overtime periods that remain after applying the
+// cancellation rows in document order.
+func declaredOvertime(overtimes []ergani.CompanyOvertime, loc *time.Location) []overtimePeriod {
+	var periods []overtimePeriod
+	for _, doc := range overtimes {
+		for _, ot := range doc.EmployeeOvertimes {
+			date := time.Date(ot.OvertimeDate.Year(), ot.OvertimeDate.Month(), ot.OvertimeDate.Day(), 0, 0, 0, 0, loc)
+			wd := ergani.WorkdayDetails{StartTime: ot.OvertimeStartTime, EndTime: ot.OvertimeEndTime}
+			start, end := wd.On(date)
+
+			if !ot.OvertimeCancellation {
+				periods = append(periods, overtimePeriod{taxID: ot.EmployeeTaxID, start: start, end: end, justification: ot.OvertimeJustification})
+				continue
+			}
+			for i, p := range periods {
+				if p.taxID == ot.EmployeeTaxID && p.start.Equal(start) && p.end.Equal(end) {
+					periods = append(periods[:i], periods[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return periods
+}
+
+// clip limits [start, end) to [from, to). Zero bounds are open.
+func clip(start, end, from, to time.Time) (time.Time, time.Time, bool) {
+	if !from.IsZero() && start.Before(from) {
+		start = from
+	}
+	if !to.IsZero() && end.After(to) {
+		end = to
+	}
+	return start, end, start.Before(end)
+}
+
+// nightHours returns the part of [start, end) between NightStart and NightEnd on
+// the wall clock, so that a night with a DST change is an hour longer or shorter.
+func nightHours(start, end time.Time) time.Duration {
+	var night time.Duration
+	loc := start.Location()
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	for day.Before(end) {
+		y, m, d := day.Date()
+		from := time.Date(y, m, d, 0, int(NightStart/time.Minute), 0, 0, loc)
+		to := time.Date(y, m, d+1, 0, int(NightEnd/time.Minute), 0, 0, loc)
+		if s, e, ok := clip(start, end, from, to); ok {
+			night += e.Sub(s)
+		}
+		day = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return night
+}
+
+// sundayHolidayHours returns the part of [start, end) that falls on Sundays and
+// public holidays.
+func sundayHolidayHours(start, end time.Time, isHoliday func(ergani.Date) bool) time.Duration {
+	var total time.Duration
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for day.Before(end) {
+		next := day.AddDate(0, 0, 1)
+		if day.Weekday() == time.Sunday || isHoliday(ergani.Date{Time: day}) {
+			if s, e, ok := clip(start, end, day, next); ok {
+				total += e.Sub(s)
+			}
+		}
+		day = next
+	}
+	return total
+}
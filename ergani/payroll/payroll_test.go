@@ -0,0 +1,166 @@
+package payroll
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+	"github.com/takispanag/ergani-go-sdk/ergani/shifts"
+)
+
+func card(taxID string, movement ergani.WorkCardMovementType, at time.Time) ergani.WorkCard {
+	return ergani.WorkCard{EmployeeTaxID: taxID, WorkCardMovementType: movement, WorkCardMovementDateTime: ergani.DateTime{Time: at}}
+}
+
+func overtime(t *testing.T, taxID string, date time.Time, from, to string, justification ergani.OvertimeJustificationType, cancel bool) ergani.Overtime {
+	t.Helper()
+	return ergani.Overtime{
+		EmployeeTaxID:         taxID,
+		OvertimeDate:          ergani.Date{Time: date},
+		OvertimeStartTime:     testutil.Clock(t, from),
+		OvertimeEndTime:       testutil.Clock(t, to),
+		OvertimeJustification: justification,
+		OvertimeCancellation:  ergani.Bool(cancel),
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2025, 8, d, h, 0, 0, 0, time.UTC) }
+
+	cards := []ergani.WorkCard{
+		// Sunday evening into Monday morning.
+		card("111111111", ergani.Arrival, day(10, 20)),
+		card("111111111", ergani.Departure, day(11, 2)),
+		// The Assumption of Mary is a national holiday.
+		card("111111111", ergani.Arrival, day(15, 9)),
+		card("111111111", ergani.Departure, day(15, 17)),
+		// Only the part after the start of the period counts.
+		card("222222222", ergani.Arrival, day(0, 23)),
+		card("222222222", ergani.Departure, day(1, 3)),
+		card("222222222", ergani.Departure, day(2, 3)),
+	}
+	overtimes := []ergani.CompanyOvertime{
+		{EmployeeOvertimes: []ergani.Overtime{
+			overtime(t, "111111111", day(15, 0), "15:00", "17:00", ergani.ExceptionalWorkload, false),
+			overtime(t, "111111111", day(11, 0), "17:00", "18:00", ergani.UrgentSeasonalTasks, false),
+			overtime(t, "222222222", day(1, 0), "01:00", "02:00", "CUSTOM", false),
+		}},
+		{EmployeeOvertimes: []ergani.Overtime{
+			overtime(t, "111111111", day(11, 0), "17:00", "18:00", ergani.UrgentSeasonalTasks, true),
+		}},
+	}
+
+	report := Aggregate(cards, overtimes, Config{From: day(1, 0), To: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), Location: time.UTC})
+	if len(report.Summaries) != 2 || len(report.Findings) != 1 {
+		t.Fatalf("Expected 2 summaries and 1 finding, got %+v", report)
+	}
+
+	s := report.Summaries[0]
+	if s.Worked != 14*time.Hour || s.Regular != 12*time.Hour || s.Night != 4*time.Hour || s.SundayHoliday != 12*time.Hour {
+		t.Errorf("Unexpected totals %+v", s)
+	}
+	if s.Overtime[ergani.ExceptionalWorkload] != 2*time.Hour || s.Overtime[ergani.UrgentSeasonalTasks] != 0 {
+		t.Errorf("Expected the cancelled overtime to be removed, got %v", s.Overtime)
+	}
+	if s := report.Summaries[1]; s.Worked != 3*time.Hour || s.Night != 3*time.Hour || s.Regular != 2*time.Hour {
+		t.Errorf("Expected the shift to be clipped to the period, got %+v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 3 || len(records[0]) != 18 || records[0][9] != "overtime_exceptional_workload_minutes" {
+		t.Fatalf("Unexpected CSV header %v", records[0])
+	}
+	if records[1][1] != "01/08/2025" || records[1][4] != "720" || records[1][9] != "120" || records[2][16] != "60" || records[2][17] != "60" {
+		t.Errorf("Unexpected CSV output %v", records)
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to read JSON: %v", err)
+	}
+	overtimeMinutes := decoded[0]["overtime_minutes"].(map[string]interface{})
+	if decoded[0]["sunday_holiday_minutes"] != float64(720) || overtimeMinutes["EXCEPTIONAL_WORKLOAD"] != float64(120) || len(overtimeMinutes) != 10 {
+		t.Errorf("Unexpected JSON output %s", buf.String())
+	}
+}
+
+func TestAggregate_DSTNight(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skipf("Time zone data not available: %v", err)
+	}
+	// Clocks go back from 04:00 to 03:00 on 26 October 2025, so 22:00-06:00 lasts 9 hours.
+	cards := []ergani.WorkCard{
+		card("111111111", ergani.Arrival, time.Date(2025, 10, 25, 20, 0, 0, 0, athens)),
+		card("111111111", ergani.Departure, time.Date(2025, 10, 26, 8, 0, 0, 0, athens)),
+	}
+
+	report := Aggregate(cards, nil, Config{Location: athens, Shifts: shifts.Options{MaxShift: 16 * time.Hour}})
+	if len(report.Summaries) != 1 {
+		t.Fatalf("Expected 1 summary, got %+v", report)
+	}
+	if s := report.Summaries[0]; s.Worked != 13*time.Hour || s.Night != 9*time.Hour {
+		t.Errorf("Expected 13h worked and 9h at night, got %v and %v", s.Worked, s.Night)
+	}
+}
+
+func TestAggregate_OvertimeNotWorked(t *testing.T) {
+	day := func(h int) time.Time { return time.Date(2025, 8, 4, h, 0, 0, 0, time.UTC) }
+	cards := []ergani.WorkCard{
+		card("111111111", ergani.Arrival, day(9)),
+		card("111111111", ergani.Departure, day(18)),
+	}
+	// 17:00-19:00 is declared but the employee left at 18:00; 20:00-21:00 overlaps nothing.
+	overtimes := []ergani.CompanyOvertime{{EmployeeOvertimes: []ergani.Overtime{
+		overtime(t, "111111111", day(0), "17:00", "19:00", ergani.ExceptionalWorkload, false),
+		overtime(t, "111111111", day(0), "17:30", "18:30", ergani.UrgentSeasonalTasks, false),
+		overtime(t, "111111111", day(0), "20:00", "21:00", ergani.ExceptionalWorkload, false),
+	}}}
+
+	report := Aggregate(cards, overtimes, Config{Location: time.UTC})
+	s := report.Summaries[0]
+	if s.Worked != 9*time.Hour || s.Regular != 8*time.Hour || s.TotalOvertime() != 4*time.Hour {
+		t.Errorf("Expected 9h worked, 8h regular and 4h declared overtime, got %+v", s)
+	}
+}
+
+func TestWriteCSV_Minutes(t *testing.T) {
+	report := Report{Summaries: []Summary{{
+		EmployeeTaxID: "111111111",
+		Worked:        8*time.Hour + 10*time.Second,
+		Regular:       7*time.Hour + 50*time.Second,
+		Overtime: map[ergani.OvertimeJustificationType]time.Duration{
+			ergani.ExceptionalWorkload: 30*time.Minute + 30*time.Second,
+			ergani.UrgentSeasonalTasks: 29*time.Minute + 40*time.Second,
+		},
+	}}}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	row := records[1]
+	// 480 worked minutes less 59 worked overtime minutes; 30 + 29 overtime minutes.
+	if row[3] != "480" || row[4] != "421" || row[17] != "59" {
+		t.Errorf("Expected 480 worked, 421 regular and 59 overtime minutes, got %v", row)
+	}
+}
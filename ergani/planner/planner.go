@@ -0,0 +1,201 @@
+// Package planner turns rotating shift patterns into work time schedules.
+//
+// A Rotation is a repeating cycle of shifts, e.g. four working days followed by two
+// rest days, or a week of mornings followed by a week of evenings. A Plan applies a
+// rotation to employees over a date range and produces the daily or weekly
+// schedules to submit, with rest days declared explicitly.
+package planner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/compliance"
+)
+
+// Shift is one day of a rotation.
+type Shift struct {
+	Name string
+	// WorkdayDetails are the working periods of the shift. A shift without periods
+	// is a rest day.
+	WorkdayDetails []ergani.WorkdayDetails
+}
+
+// Rest is a rest day in a rotation.
+var Rest = Shift{Name: "REST"}
+
+// IsRest reports whether the shift is a rest day.
+func (s Shift) IsRest() bool {
+	return len(s.WorkdayDetails) == 0
+}
+
+// details returns the schedule rows of the shift, declaring rest days as RestDay.
+func (s Shift) details() []ergani.WorkdayDetails {
+	if s.IsRest() {
+		return []ergani.WorkdayDetails{{WorkType: ergani.RestDay}}
+	}
+	details := make([]ergani.WorkdayDetails, len(s.WorkdayDetails))
+	copy(details, s.WorkdayDetails)
+	return details
+}
+
+// Rotation is a cycle of shifts that repeats day after day.
+type Rotation struct {
+	Name  string
+	Cycle []Shift
+}
+
+// Employee is an employee assigned to a rotation.
+type Employee struct {
+	TaxID     string
+	LastName  string
+	FirstName string
+	// Offset is the position in the cycle the employee starts at on the first day
+	// of the plan, so that employees on the same rotation can be staggered.
+	Offset int
+}
+
+// Assignment is the shift of an employee on a date.
+type Assignment struct {
+	Employee Employee
+	Date     ergani.Date
+	Shift    Shift
+}
+
+// Plan is a rotation applied to employees over a date range.
+type Plan struct {
+	BusinessBranchNumber int
+	Start                time.Time
+	End                  time.Time
+	// Assignments are ordered by date and then by employee in input order.
+	Assignments []Assignment
+}
+
+// New applies the rotation to the employees for every day from start to end
+// inclusive.
+func New(businessBranchNumber int, rotation Rotation, employees []Employee, start, end time.Time) (*Plan, error) {
+	if len(rotation.Cycle) == 0 {
+		return nil, &ergani.ValidationError{Field: "Cycle", Message: "rotation has no shifts"}
+	}
+	start, end = day(start), day(end)
+	if end.Before(start) {
+		return nil, &ergani.ValidationError{Field: "End", Message: "plan ends before it starts"}
+	}
+
+	p := &Plan{BusinessBranchNumber: businessBranchNumber, Start: start, End: end}
+	n := len(rotation.Cycle)
+	for i, date := 0, start; !date.After(end); i, date = i+1, date.AddDate(0, 0, 1) {
+		for _, e := range employees {
+			shift := rotation.Cycle[((i+e.Offset)%n+n)%n]
+			p.Assignments = append(p.Assignments, Assignment{Employee: e, Date: ergani.Date{Time: date}, Shift: shift})
+		}
+	}
+	return p, nil
+}
+
+// Daily returns the plan as a single daily schedule covering its date range.
+func (p *Plan) Daily() ergani.CompanyDailySchedule {
+	startDate, endDate := ergani.Date{Time: p.Start}, ergani.Date{Time: p.End}
+	schedule := ergani.CompanyDailySchedule{
+		BusinessBranchNumber: p.BusinessBranchNumber,
+		StartDate:            &startDate,
+		EndDate:              &endDate,
+	}
+	for _, a := range p.Assignments {
+		schedule.EmployeeSchedules = append(schedule.EmployeeSchedules, ergani.EmployeeDailySchedule{
+			EmployeeTaxID:     a.Employee.TaxID,
+			EmployeeLastName:  a.Employee.LastName,
+			EmployeeFirstName: a.Employee.FirstName,
+			ScheduleDate:      a.Date,
+			WorkdayDetails:    a.Shift.details(),
+		})
+	}
+	return schedule
+}
+
+// Weekly returns the plan as one weekly schedule per calendar week (Monday to
+// Sunday), clipped to the plan's date range. Since rotations rarely repeat weekly,
+// every week is declared separately.
+func (p *Plan) Weekly() []ergani.CompanyWeeklySchedule {
+	var schedules []ergani.CompanyWeeklySchedule
+	for _, a := range p.Assignments {
+		weekStart := startOfWeek(a.Date.Time)
+		if weekStart.Before(p.Start) {
+			weekStart = p.Start
+		}
+		n := len(schedules)
+		if n == 0 || !schedules[n-1].StartDate.Equal(weekStart) {
+			weekEnd := startOfWeek(a.Date.Time).AddDate(0, 0, 6)
+			if weekEnd.After(p.End) {
+				weekEnd = p.End
+			}
+			schedules = append(schedules, ergani.CompanyWeeklySchedule{
+				BusinessBranchNumber: p.BusinessBranchNumber,
+				StartDate:            ergani.Date{Time: weekStart},
+				EndDate:              ergani.Date{Time: weekEnd},
+			})
+			n++
+		}
+		schedules[n-1].EmployeeSchedules = append(schedules[n-1].EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+			EmployeeTaxID:     a.Employee.TaxID,
+			EmployeeLastName:  a.Employee.LastName,
+			EmployeeFirstName: a.Employee.FirstName,
+			ScheduleDay:       ergani.Weekday{Weekday: a.Date.Weekday()},
+			WorkdayDetails:    a.Shift.details(),
+		})
+	}
+	return schedules
+}
+
+// Check runs the compliance rules of the profile against the plan.
+func (p *Plan) Check(profile compliance.Profile) []compliance.Finding {
+	return compliance.NewEngine(profile).Check(compliance.Input{
+		DailySchedules: []ergani.CompanyDailySchedule{p.Daily()},
+		Location:       p.Start.Location(),
+	})
+}
+
+// DiffDaily returns the amendment that turns the previously submitted daily
+// schedule into this plan. See ergani.DiffDailySchedule.
+func (p *Plan) DiffDaily(previous ergani.CompanyDailySchedule) ergani.CompanyDailySchedule {
+	return ergani.DiffDailySchedule(previous, p.Daily())
+}
+
+// DiffWeekly returns the amendments that turn the previously submitted weekly
+// schedules into this plan, matched by start date. Weeks without changes are
+// omitted; weeks without a previous schedule are returned in full. To submit a
+// changed week, pass its previous and planned schedules to Client.AmendWeeklySchedule.
+func (p *Plan) DiffWeekly(previous []ergani.CompanyWeeklySchedule) []ergani.CompanyWeeklySchedule {
+	old := make(map[string]ergani.CompanyWeeklySchedule, len(previous))
+	for _, s := range previous {
+		old[weekKey(s.StartDate)] = s
+	}
+
+	var amendments []ergani.CompanyWeeklySchedule
+	for _, s := range p.Weekly() {
+		prev, ok := old[weekKey(s.StartDate)]
+		if !ok {
+			amendments = append(amendments, s)
+			continue
+		}
+		amendment := ergani.DiffWeeklySchedule(prev, s)
+		if len(amendment.EmployeeSchedules) > 0 {
+			amendments = append(amendments, amendment)
+		}
+	}
+	return amendments
+}
+
+func weekKey(d ergani.Date) string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year(), d.Month(), d.Day())
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfWeek(t time.Time) time.Time {
+	d := day(t)
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,77 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/compliance"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+func shift(t *testing.T, name, from, to string) Shift {
+	t.Helper()
+	return Shift{Name: name, WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, from, to)}}
+}
+
+func TestPlan(t *testing.T) {
+	morning := shift(t, "MORNING", "06:00", "14:00")
+	rotation := Rotation{Name: "4-on/2-off", Cycle: []Shift{morning, morning, morning, morning, Rest, Rest}}
+	employees := []Employee{
+		{TaxID: "111111111", LastName: "Doe", FirstName: "John"},
+		{TaxID: "222222222", LastName: "Roe", FirstName: "Jane", Offset: 2},
+	}
+
+	// Wednesday to Tuesday of the following week.
+	start := time.Date(2025, 7, 9, 0, 0, 0, 0, time.UTC)
+	plan, err := New(1, rotation, employees, start, start.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	daily := plan.Daily()
+	if len(daily.EmployeeSchedules) != 14 || daily.BusinessBranchNumber != 1 {
+		t.Fatalf("Expected 14 employee days, got %d", len(daily.EmployeeSchedules))
+	}
+	// John rests on days 4 and 5, Jane on days 2 and 3.
+	if es := daily.EmployeeSchedules[2*4]; es.EmployeeTaxID != "111111111" || es.WorkdayDetails[0].WorkType != ergani.RestDay {
+		t.Errorf("Expected a rest day for John on day 4, got %+v", es)
+	}
+	if es := daily.EmployeeSchedules[2*2+1]; es.EmployeeTaxID != "222222222" || es.WorkdayDetails[0].WorkType != ergani.RestDay {
+		t.Errorf("Expected a rest day for Jane on day 2, got %+v", es)
+	}
+
+	weekly := plan.Weekly()
+	if len(weekly) != 2 {
+		t.Fatalf("Expected the plan to span two weeks, got %d", len(weekly))
+	}
+	if !weekly[0].StartDate.Equal(start) || weekly[0].EndDate.Weekday() != time.Sunday || len(weekly[0].EmployeeSchedules) != 10 {
+		t.Errorf("Unexpected first week %s - %s with %d rows", weekly[0].StartDate.Format("02/01"), weekly[0].EndDate.Format("02/01"), len(weekly[0].EmployeeSchedules))
+	}
+	if weekly[1].StartDate.Weekday() != time.Monday || !weekly[1].EndDate.Equal(plan.End) {
+		t.Errorf("Unexpected second week %s - %s", weekly[1].StartDate.Format("02/01"), weekly[1].EndDate.Format("02/01"))
+	}
+
+	if findings := plan.Check(compliance.DefaultProfile); compliance.HasErrors(findings) {
+		t.Errorf("Expected a 4-on/2-off plan to comply, got %v", findings)
+	}
+
+	t.Run("Diff", func(t *testing.T) {
+		evening := shift(t, "EVENING", "14:00", "22:00")
+		changed, _ := New(1, Rotation{Cycle: []Shift{morning, morning, morning, evening, Rest, Rest}}, employees, start, start.AddDate(0, 0, 6))
+
+		amendment := changed.DiffDaily(daily)
+		if len(amendment.EmployeeSchedules) != 2 {
+			t.Fatalf("Expected only the two changed days, got %+v", amendment.EmployeeSchedules)
+		}
+
+		weeks := changed.DiffWeekly(weekly)
+		if len(weeks) != 1 || len(weeks[0].EmployeeSchedules) != 2 {
+			t.Errorf("Expected two changed weekdays in the first week only, got %+v", weeks)
+		}
+	})
+
+	if _, err := New(1, Rotation{}, employees, start, start); err == nil {
+		t.Error("Expected an error for an empty rotation")
+	}
+}
@@ -0,0 +1,334 @@
+// Package quota tracks the overtime hours submitted per employee and year and
+// warns about or blocks submissions that would exceed the annual cap.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/compliance"
+)
+
+// DefaultCap is the default annual overtime cap per employee, the same as the
+// cap of compliance.DefaultProfile.
+const DefaultCap = compliance.DefaultAnnualOvertimeCap
+
+// ErrNotRecorded is returned together with the responses when Ergani accepted the
+// overtime but the Store failed to record it. The submission succeeded and must
+// not be repeated.
+var ErrNotRecorded = errors.New("overtime accepted but not recorded")
+
+// Mode decides what happens when a submission would exceed the cap.
+type Mode int
+
+const (
+	// Warn reports the exceedance and submits anyway.
+	Warn Mode = iota
+	// Block refuses to submit.
+	Block
+)
+
+// Store persists the overtime used per employee and year.
+type Store interface {
+	Used(ctx context.Context, taxID string, year int) (time.Duration, error)
+	// Add adds d, which is negative for cancellations, to the overtime used.
+	Add(ctx context.Context, taxID string, year int, d time.Duration) error
+}
+
+// MemoryStore is an in-memory Store.
+type MemoryStore struct {
+	mu   sync.Mutex
+	used map[string]time.Duration
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{used: make(map[string]time.Duration)}
+}
+
+// Used implements Store.
+func (s *MemoryStore) Used(_ context.Context, taxID string, year int) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used[storeKey(taxID, year)], nil
+}
+
+// Add implements Store.
+func (s *MemoryStore) Add(_ context.Context, taxID string, year int, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.used[storeKey(taxID, year)] += d
+	return nil
+}
+
+func storeKey(taxID string, year int) string {
+	return fmt.Sprintf("%s/%d", taxID, year)
+}
+
+// Exceedance describes an employee whose overtime would exceed the cap.
+type Exceedance struct {
+	EmployeeTaxID string
+	Year          int
+	Used          time.Duration
+	Requested     time.Duration
+	Cap           time.Duration
+}
+
+// String implements the fmt.Stringer interface.
+func (e Exceedance) String() string {
+	return fmt.Sprintf("%s: %v used and %v requested in %d exceed the cap of %v", e.EmployeeTaxID, e.Used, e.Requested, e.Year, e.Cap)
+}
+
+// ExceededError is returned in Block mode when a submission would exceed the cap.
+type ExceededError struct {
+	Exceedances []Exceedance
+}
+
+// Error implements the standard error interface.
+func (e *ExceededError) Error() string {
+	parts := make([]string, len(e.Exceedances))
+	for i, ex := range e.Exceedances {
+		parts[i] = ex.String()
+	}
+	return "annual overtime cap exceeded: " + strings.Join(parts, "; ")
+}
+
+// Usage is the overtime used and remaining for an employee in a year.
+type Usage struct {
+	EmployeeTaxID string
+	Year          int
+	Used          time.Duration
+	Remaining     time.Duration
+}
+
+// Submitter submits overtime. It is implemented by *ergani.Client.
+type Submitter interface {
+	SubmitOvertime(ctx context.Context, companyOvertimes []ergani.CompanyOvertime) ([]ergani.SubmissionResponse, error)
+}
+
+// Config configures a Tracker.
+type Config struct {
+	// Cap is the annual overtime cap per employee. Defaults to DefaultCap.
+	Cap  time.Duration
+	Mode Mode
+	// OnExceeded, if set, is called in Warn mode for every exceedance.
+	OnExceeded func(Exceedance)
+}
+
+// Tracker accumulates submitted overtime in a Store. SubmitOvertime and
+// CancelOvertime calls that concern the same employees are serialized, so that
+// concurrent submissions cannot together exceed the cap. Trackers in other
+// processes sharing the Store are not coordinated.
+type Tracker struct {
+	store  Store
+	config Config
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewTracker creates a Tracker backed by store.
+func NewTracker(store Store, config Config) *Tracker {
+	if config.Cap == 0 {
+		config.Cap = DefaultCap
+	}
+	return &Tracker{store: store, config: config, locks: make(map[string]*sync.Mutex)}
+}
+
+// Check returns the employees whose overtime would exceed the cap if the documents
+// were submitted.
+func (t *Tracker) Check(ctx context.Context, companyOvertimes []ergani.CompanyOvertime) ([]Exceedance, error) {
+	var exceedances []Exceedance
+	reqs := requested(companyOvertimes)
+	for _, key := range sortedKeys(reqs) {
+		req := reqs[key]
+		if req <= 0 {
+			continue
+		}
+		used, err := t.store.Used(ctx, key.taxID, key.year)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overtime quota: %w", err)
+		}
+		if used+req > t.config.Cap {
+			exceedances = append(exceedances, Exceedance{
+				EmployeeTaxID: key.taxID,
+				Year:          key.year,
+				Used:          used,
+				Requested:     req,
+				Cap:           t.config.Cap,
+			})
+		}
+	}
+	return exceedances, nil
+}
+
+// Record adds the overtime of accepted documents to the store. Cancellation rows
+// give their hours back.
+func (t *Tracker) Record(ctx context.Context, companyOvertimes []ergani.CompanyOvertime) error {
+	req := requested(companyOvertimes)
+	for _, key := range sortedKeys(req) {
+		if err := t.store.Add(ctx, key.taxID, key.year, req[key]); err != nil {
+			return fmt.Errorf("failed to record overtime quota: %w", err)
+		}
+	}
+	return nil
+}
+
+// SubmitOvertime checks the documents against the cap, submits them and records
+// the overtime once they are accepted. In Block mode an *ExceededError is returned
+// without submitting; in Warn mode OnExceeded is called and the documents are
+// submitted anyway. If the accepted overtime cannot be recorded, the responses are
+// returned with an error wrapping ErrNotRecorded.
+func (t *Tracker) SubmitOvertime(ctx context.Context, submitter Submitter, companyOvertimes []ergani.CompanyOvertime) ([]ergani.SubmissionResponse, error) {
+	unlock := t.lock(companyOvertimes)
+	defer unlock()
+
+	exceedances, err := t.Check(ctx, companyOvertimes)
+	if err != nil {
+		return nil, err
+	}
+	if len(exceedances) > 0 {
+		if t.config.Mode == Block {
+			return nil, &ExceededError{Exceedances: exceedances}
+		}
+		if t.config.OnExceeded != nil {
+			for _, e := range exceedances {
+				t.config.OnExceeded(e)
+			}
+		}
+	}
+
+	responses, err := submitter.SubmitOvertime(ctx, companyOvertimes)
+	if err != nil {
+		return responses, err
+	}
+	if err := t.Record(ctx, companyOvertimes); err != nil {
+		return responses, fmt.Errorf("%w: %v", ErrNotRecorded, err)
+	}
+	return responses, nil
+}
+
+// lock locks the employees of the documents in tax ID order and returns a
+// function that unlocks them.
+func (t *Tracker) lock(companyOvertimes []ergani.CompanyOvertime) func() {
+	var taxIDs []string
+	for _, key := range sortedKeys(requested(companyOvertimes)) {
+		if len(taxIDs) == 0 || taxIDs[len(taxIDs)-1] != key.taxID {
+			taxIDs = append(taxIDs, key.taxID)
+		}
+	}
+
+	locks := make([]*sync.Mutex, len(taxIDs))
+	t.mu.Lock()
+	for i, taxID := range taxIDs {
+		l, ok := t.locks[taxID]
+		if !ok {
+			l = &sync.Mutex{}
+			t.locks[taxID] = l
+		}
+		locks[i] = l
+	}
+	t.mu.Unlock()
+
+	for _, l := range locks {
+		l.Lock()
+	}
+	return func() {
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+}
+
+// CancelOvertime cancels previously submitted overtime like
+// ergani.Client.CancelOvertime and gives the cancelled hours back once the
+// cancellation is accepted. Use it instead of the client method, which does not
+// update the tracker.
+func (t *Tracker) CancelOvertime(ctx context.Context, submitter Submitter, original ergani.SubmissionResponse, submitted ergani.CompanyOvertime, rows ...ergani.Overtime) ([]ergani.SubmissionResponse, error) {
+	cancellation, err := ergani.NewOvertimeCancellation(original, submitted, rows...)
+	if err != nil {
+		return nil, err
+	}
+	return t.SubmitOvertime(ctx, submitter, []ergani.CompanyOvertime{cancellation})
+}
+
+// Remaining returns the overtime an employee may still work in a year. It is
+// negative if the cap was already exceeded.
+func (t *Tracker) Remaining(ctx context.Context, taxID string, year int) (time.Duration, error) {
+	used, err := t.store.Used(ctx, taxID, year)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read overtime quota: %w", err)
+	}
+	return t.config.Cap - used, nil
+}
+
+// Report returns the usage of the employees in a year, in the given order.
+func (t *Tracker) Report(ctx context.Context, year int, taxIDs ...string) ([]Usage, error) {
+	report := make([]Usage, 0, len(taxIDs))
+	for _, taxID := range taxIDs {
+		used, err := t.store.Used(ctx, taxID, year)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overtime quota: %w", err)
+		}
+		report = append(report, Usage{EmployeeTaxID: taxID, Year: year, Used: used, Remaining: t.config.Cap - used})
+	}
+	return report, nil
+}
+
+// OvertimeUsed loads the recorded overtime of the employees and years of the
+// documents and returns a lookup of it, e.g. for compliance.Input.OvertimeUsed
+// when checking the documents. Employees and years not in the documents report no
+// usage.
+func (t *Tracker) OvertimeUsed(ctx context.Context, companyOvertimes []ergani.CompanyOvertime) (func(taxID string, year int) time.Duration, error) {
+	used := make(map[usageKey]time.Duration)
+	for key := range requested(companyOvertimes) {
+		d, err := t.store.Used(ctx, key.taxID, key.year)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overtime quota: %w", err)
+		}
+		used[key] = d
+	}
+	return func(taxID string, year int) time.Duration {
+		return used[usageKey{taxID, year}]
+	}, nil
+}
+
+type usageKey struct {
+	taxID string
+	year  int
+}
+
+// requested sums the overtime of the documents per employee and year.
+func requested(companyOvertimes []ergani.CompanyOvertime) map[usageKey]time.Duration {
+	req := make(map[usageKey]time.Duration)
+	for _, doc := range companyOvertimes {
+		for _, ot := range doc.EmployeeOvertimes {
+			d := ergani.WorkdayDetails{StartTime: ot.OvertimeStartTime, EndTime: ot.OvertimeEndTime}.Duration()
+			if ot.OvertimeCancellation {
+				d = -d
+			}
+			req[usageKey{ot.EmployeeTaxID, ot.OvertimeDate.Year()}] += d
+		}
+	}
+	return req
+}
+
+func sortedKeys(m map[usageKey]time.Duration) []usageKey {
+	keys := make([]usageKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].taxID != keys[j].taxID {
+			return keys[i].taxID < keys[j].taxID
+		}
+		return keys[i].year < keys[j].year
+	})
+	return keys
+}
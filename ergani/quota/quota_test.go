@@ -0,0 +1,188 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+type fakeSubmitter struct {
+	calls int
+	err   error
+}
+
+func (f *fakeSubmitter) SubmitOvertime(context.Context, []ergani.CompanyOvertime) ([]ergani.SubmissionResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []ergani.SubmissionResponse{{ID: "1", Protocol: "proto"}}, nil
+}
+
+func overtimeDoc(t *testing.T, taxID string, from, to string, cancel bool) []ergani.CompanyOvertime {
+	t.Helper()
+	return []ergani.CompanyOvertime{{EmployeeOvertimes: []ergani.Overtime{{
+		EmployeeTaxID:        taxID,
+		OvertimeDate:         ergani.Date{Time: time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)},
+		OvertimeStartTime:    testutil.Clock(t, from),
+		OvertimeEndTime:      testutil.Clock(t, to),
+		OvertimeCancellation: ergani.Bool(cancel),
+	}}}}
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Add(ctx, "111111111", 2025, 147*time.Hour)
+
+	submitter := &fakeSubmitter{}
+	tracker := NewTracker(store, Config{Mode: Block})
+
+	_, err := tracker.SubmitOvertime(ctx, submitter, overtimeDoc(t, "111111111", "17:00", "21:00", false))
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Exceedances[0].Requested != 4*time.Hour || submitter.calls != 0 {
+		t.Fatalf("Expected the submission to be blocked, got %v after %d calls", err, submitter.calls)
+	}
+
+	if _, err := tracker.SubmitOvertime(ctx, submitter, overtimeDoc(t, "111111111", "22:00", "01:00", false)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining, _ := tracker.Remaining(ctx, "111111111", 2025); remaining != 0 {
+		t.Errorf("Expected no remaining quota after a 3h night overtime, got %v", remaining)
+	}
+
+	if _, err := tracker.SubmitOvertime(ctx, submitter, overtimeDoc(t, "111111111", "22:00", "01:00", true)); err != nil {
+		t.Fatalf("Unexpected error for a cancellation: %v", err)
+	}
+	report, _ := tracker.Report(ctx, 2025, "111111111", "222222222")
+	if report[0].Remaining != 3*time.Hour || report[1].Used != 0 || report[1].Remaining != DefaultCap {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	t.Run("Warn", func(t *testing.T) {
+		var warned []Exceedance
+		tracker := NewTracker(store, Config{Mode: Warn, Cap: 148 * time.Hour, OnExceeded: func(e Exceedance) { warned = append(warned, e) }})
+		if _, err := tracker.SubmitOvertime(ctx, submitter, overtimeDoc(t, "111111111", "17:00", "19:00", false)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(warned) != 1 || warned[0].Used != 147*time.Hour {
+			t.Errorf("Expected a warning, got %+v", warned)
+		}
+		used, err := tracker.OvertimeUsed(ctx, overtimeDoc(t, "111111111", "17:00", "19:00", false))
+		if err != nil || used("111111111", 2025) != 149*time.Hour {
+			t.Errorf("Expected the overtime to be recorded, got %v, %v", used("111111111", 2025), err)
+		}
+	})
+
+	t.Run("FailedSubmission", func(t *testing.T) {
+		doc := overtimeDoc(t, "222222222", "17:00", "19:00", false)
+		before, _ := tracker.OvertimeUsed(ctx, doc)
+		if _, err := tracker.SubmitOvertime(ctx, &fakeSubmitter{err: errors.New("boom")}, doc); err == nil {
+			t.Fatal("Expected the submission error")
+		}
+		if after, _ := tracker.OvertimeUsed(ctx, doc); after("222222222", 2025) != before("222222222", 2025) {
+			t.Errorf("Expected no overtime to be recorded for a failed submission, got %v", after("222222222", 2025))
+		}
+	})
+}
+
+// failingStore fails every read.
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) Used(context.Context, string, int) (time.Duration, error) {
+	return 0, errors.New("connection lost")
+}
+
+func TestTracker_OvertimeUsedError(t *testing.T) {
+	tracker := NewTracker(failingStore{NewMemoryStore()}, Config{})
+	if used, err := tracker.OvertimeUsed(context.Background(), overtimeDoc(t, "111111111", "17:00", "19:00", false)); err == nil || used != nil {
+		t.Errorf("Expected the store error, got %v", err)
+	}
+}
+
+func TestTracker_CancelOvertime(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	submitter := &fakeSubmitter{}
+	tracker := NewTracker(store, Config{Mode: Block})
+
+	doc := overtimeDoc(t, "111111111", "17:00", "21:00", false)
+	responses, err := tracker.SubmitOvertime(ctx, submitter, doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := tracker.CancelOvertime(ctx, submitter, responses[0], doc[0]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if used, _ := store.Used(ctx, "111111111", 2025); used != 0 || submitter.calls != 2 {
+		t.Errorf("Expected the cancelled hours to be given back, got %v after %d calls", used, submitter.calls)
+	}
+
+	if _, err := tracker.CancelOvertime(ctx, submitter, ergani.SubmissionResponse{}, doc[0]); err == nil || submitter.calls != 2 {
+		t.Errorf("Expected an error without submitting for a response without protocol, got %v", err)
+	}
+}
+
+// slowSubmitter accepts every submission after a delay.
+type slowSubmitter struct{}
+
+func (slowSubmitter) SubmitOvertime(context.Context, []ergani.CompanyOvertime) ([]ergani.SubmissionResponse, error) {
+	time.Sleep(20 * time.Millisecond)
+	return []ergani.SubmissionResponse{{ID: "1", Protocol: "proto"}}, nil
+}
+
+func TestTracker_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Add(ctx, "111111111", 2025, 146*time.Hour)
+	tracker := NewTracker(store, Config{Mode: Block})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var blocked int
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var exceeded *ExceededError
+			_, err := tracker.SubmitOvertime(ctx, slowSubmitter{}, overtimeDoc(t, "111111111", "17:00", "19:00", false))
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.As(err, &exceeded) {
+				blocked++
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if used, _ := store.Used(ctx, "111111111", 2025); used != 150*time.Hour || blocked != 2 {
+		t.Errorf("Expected 2 submissions up to the cap and 2 blocked, got %v used and %d blocked", used, blocked)
+	}
+}
+
+// unrecordedStore fails to record overtime.
+type unrecordedStore struct {
+	*MemoryStore
+}
+
+func (unrecordedStore) Add(context.Context, string, int, time.Duration) error {
+	return errors.New("disk full")
+}
+
+func TestTracker_NotRecorded(t *testing.T) {
+	tracker := NewTracker(unrecordedStore{NewMemoryStore()}, Config{})
+	responses, err := tracker.SubmitOvertime(context.Background(), &fakeSubmitter{}, overtimeDoc(t, "111111111", "17:00", "19:00", false))
+	if !errors.Is(err, ErrNotRecorded) || len(responses) != 1 {
+		t.Errorf("Expected the responses with ErrNotRecorded, got %v, %v", responses, err)
+	}
+}
@@ -0,0 +1,107 @@
+package ergani
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ReceiptWriter creates the files that downloaded receipts are written to.
+// It allows receipts to be stored outside the local file system (e.g., in object storage).
+type ReceiptWriter interface {
+	Create(name string) (io.WriteCloser, error)
+}
+
+// DirReceiptWriter is a ReceiptWriter that stores receipts as files in a directory.
+type DirReceiptWriter string
+
+// Create implements the ReceiptWriter interface. It fails if the file already
+// exists, so that the receipt of one submission never overwrites another.
+func (d DirReceiptWriter) Create(name string) (io.WriteCloser, error) {
+	return os.OpenFile(filepath.Join(string(d), name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+}
+
+// ReceiptFileName returns the file name under which the receipt of a submission is
+// saved. It is derived from the protocol: letters (including Greek) and digits are
+// kept, other characters that are unsafe in file names are replaced and a short
+// hash of the protocol is appended, so that distinct protocols never share a name.
+func ReceiptFileName(submission SubmissionResponse) string {
+	replaced := false
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		replaced = true
+		return '_'
+	}, submission.Protocol)
+	if replaced {
+		sum := sha256.Sum256([]byte(submission.Protocol))
+		name += "-" + hex.EncodeToString(sum[:4])
+	}
+	return name + ".pdf"
+}
+
+// DownloadReceipt streams the official receipt (proof of submission) that Ergani
+// provides for a submission. The caller must close the returned reader.
+func (c *Client) DownloadReceipt(ctx context.Context, submission SubmissionResponse) (io.ReadCloser, error) {
+	if submission.Protocol == "" {
+		return nil, &ValidationError{Field: "Protocol", Message: "a receipt can only be downloaded for a submission with a protocol"}
+	}
+
+	endpoint := c.baseURL.JoinPath("/Documents/Submissions", url.PathEscape(submission.Protocol), "Receipt")
+	resp, err := c.requestURL(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		if err := resp.Body.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close response body: %w", err)
+		}
+		return nil, fmt.Errorf("no receipt is available for submission %s", submission.Protocol)
+	}
+
+	return resp.Body, nil
+}
+
+// SaveReceipt downloads the receipt of a submission and writes it through w under
+// the name returned by ReceiptFileName, which is also returned.
+func (c *Client) SaveReceipt(ctx context.Context, w ReceiptWriter, submission SubmissionResponse) (string, error) {
+	receipt, err := c.DownloadReceipt(ctx, submission)
+	if err != nil {
+		return "", err
+	}
+
+	name := ReceiptFileName(submission)
+	file, err := w.Create(name)
+	if err != nil {
+		closeErr := receipt.Close()
+		if closeErr != nil {
+			return "", fmt.Errorf("failed to create receipt file %s: %v (and failed to close body: %v)", name, err, closeErr)
+		}
+		return "", fmt.Errorf("failed to create receipt file %s: %w", name, err)
+	}
+
+	_, copyErr := io.Copy(file, receipt)
+	closeErr := receipt.Close()
+	fileErr := file.Close()
+
+	if copyErr != nil {
+		return "", fmt.Errorf("failed to write receipt %s: %w", name, copyErr)
+	}
+	if closeErr != nil {
+		return "", fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+	if fileErr != nil {
+		return "", fmt.Errorf("failed to close receipt file %s: %w", name, fileErr)
+	}
+
+	return name, nil
+}
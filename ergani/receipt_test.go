@@ -0,0 +1,78 @@
+package ergani
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReceiptFileName(t *testing.T) {
+	if name := ReceiptFileName(SubmissionResponse{Protocol: "ΕΡΓ-123.45"}); name != "ΕΡΓ-123.45.pdf" {
+		t.Errorf("Expected Greek letters to be kept, got '%s'", name)
+	}
+
+	slash := ReceiptFileName(SubmissionResponse{Protocol: "ΕΡΓ/123 45"})
+	space := ReceiptFileName(SubmissionResponse{Protocol: "ΕΡΓ 123/45"})
+	if !strings.HasPrefix(slash, "ΕΡΓ_123_45-") || slash == space {
+		t.Errorf("Expected distinct sanitized names, got '%s' and '%s'", slash, space)
+	}
+	if ReceiptFileName(SubmissionResponse{Protocol: "ΕΡΓ123"}) == ReceiptFileName(SubmissionResponse{Protocol: "ΑΡΓ123"}) {
+		t.Error("Expected protocols that differ in Greek letters to have distinct names")
+	}
+}
+
+func TestDownloadReceipt(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	receipt, err := client.DownloadReceipt(context.Background(), SubmissionResponse{Protocol: "proto456"})
+	if err != nil {
+		t.Fatalf("Expected no error on DownloadReceipt, but got: %v", err)
+	}
+	defer receipt.Close()
+
+	content, err := io.ReadAll(receipt)
+	if err != nil {
+		t.Fatalf("Failed to read receipt: %v", err)
+	}
+	if string(content) != "%PDF-1.4 receipt" {
+		t.Errorf("Unexpected receipt content %q", content)
+	}
+
+	if _, err := client.DownloadReceipt(context.Background(), SubmissionResponse{ID: "sub123"}); err == nil {
+		t.Error("Expected an error for a submission without a protocol")
+	}
+}
+
+func TestSaveReceipt(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+	dir := t.TempDir()
+
+	name, err := client.SaveReceipt(context.Background(), DirReceiptWriter(dir), SubmissionResponse{Protocol: "proto456"})
+	if err != nil {
+		t.Fatalf("Expected no error on SaveReceipt, but got: %v", err)
+	}
+	if name != "proto456.pdf" {
+		t.Errorf("Expected receipt name 'proto456.pdf', got '%s'", name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("Failed to read saved receipt: %v", err)
+	}
+	if string(content) != "%PDF-1.4 receipt" {
+		t.Errorf("Unexpected saved receipt content %q", content)
+	}
+
+	if _, err := client.SaveReceipt(context.Background(), DirReceiptWriter(dir), SubmissionResponse{Protocol: "proto456"}); err == nil {
+		t.Error("Expected an error instead of overwriting an existing receipt")
+	}
+}
@@ -0,0 +1,238 @@
+// Package reconcile compares the declared work time schedules with the work card
+// movements that were actually submitted and reports the deviations.
+package reconcile
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/shifts"
+	"github.com/takispanag/ergani-go-sdk/ergani/tolerance"
+)
+
+// DefaultTolerance is the default deviation from the declared times that is not reported.
+const DefaultTolerance = 15 * time.Minute
+
+// DeviationKind is the type of a schedule deviation.
+type DeviationKind string
+
+const (
+	// EarlyArrival is an arrival before the declared start beyond the tolerance.
+	EarlyArrival DeviationKind = "EARLY_ARRIVAL"
+	// LateDeparture is a departure after the declared end beyond the tolerance.
+	LateDeparture DeviationKind = "LATE_DEPARTURE"
+	// WorkOnRestDay is a shift on a day declared as RestDay.
+	WorkOnRestDay DeviationKind = "WORK_ON_REST_DAY"
+	// WorkOnAbsentDay is a shift on a day declared as Absent.
+	WorkOnAbsentDay DeviationKind = "WORK_ON_ABSENT_DAY"
+	// UnscheduledWork is a shift on a day without a declared schedule or outside
+	// all declared working periods.
+	UnscheduledWork DeviationKind = "UNSCHEDULED_WORK"
+)
+
+// Deviation is a difference between the declared schedule and a worked shift.
+type Deviation struct {
+	Kind          DeviationKind
+	EmployeeTaxID string
+	Date          ergani.Date
+	// ScheduledStart and ScheduledEnd are the declared working period the shift was
+	// matched to. They are zero for rest, absent and unscheduled days.
+	ScheduledStart time.Time
+	ScheduledEnd   time.Time
+	Shift          shifts.Shift
+	// Amount is the time worked outside the declared period.
+	Amount time.Duration
+}
+
+// Options configures Reconcile.
+type Options struct {
+	// Tolerance is the deviation from the declared start and end that is not
+	// reported. Defaults to DefaultTolerance; use a negative value for none.
+	Tolerance time.Duration
+	// Location is used to resolve the dates of shifts and declared periods.
+	// Defaults to time.Local.
+	Location *time.Location
+	// Shifts configures how work cards are paired into shifts.
+	Shifts shifts.Options
+	// Model, if set, replaces Tolerance with the grace periods and rounding of a
+	// tolerance model. Early arrivals and late departures are reported when they
+	// need Overtime, with the rounded deviation as Amount.
+	Model *tolerance.Model
+	// EmployerTaxID selects the rules of Model.
+	EmployerTaxID string
+}
+
+// Report is the result of a reconciliation.
+type Report struct {
+	Deviations []Deviation
+	// Findings are the work card movements that could not be paired into shifts.
+	Findings []shifts.Finding
+}
+
+// Reconcile pairs the work cards into shifts and compares every shift with the
+// schedule declared for its employee on the day the shift started.
+func Reconcile(schedules *ergani.ScheduleIndex, cards []ergani.WorkCard, opts Options) Report {
+	if opts.Tolerance == 0 {
+		opts.Tolerance = DefaultTolerance
+	} else if opts.Tolerance < 0 {
+		opts.Tolerance = 0
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Shifts.Location == nil {
+		opts.Shifts.Location = opts.Location
+	}
+
+	result := shifts.Reconstruct(cards, opts.Shifts)
+	report := Report{Findings: result.Findings}
+	for _, shift := range result.Shifts {
+		report.Deviations = append(report.Deviations, reconcileShift(schedules, shift, opts)...)
+	}
+	return report
+}
+
+func reconcileShift(schedules *ergani.ScheduleIndex, shift shifts.Shift, opts Options) []Deviation {
+	start, end := shift.Start().In(opts.Location), shift.End().In(opts.Location)
+	date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, opts.Location)
+	deviation := Deviation{EmployeeTaxID: shift.EmployeeTaxID, Date: ergani.Date{Time: date}, Shift: shift, Amount: shift.Duration()}
+
+	// A night period declared on the previous day can cover a shift that starts
+	// after midnight, whatever is declared for the day itself.
+	var periods []period
+	if prev, ok := schedules.Lookup(shift.EmployeeTaxID, date.AddDate(0, 0, -1)); ok {
+		periods = workPeriods(prev, date.AddDate(0, 0, -1))
+	}
+	scheduledStart, scheduledEnd := matchPeriods(periods, start, end)
+	if !scheduledStart.IsZero() {
+		deviation.Date = ergani.Date{Time: date.AddDate(0, 0, -1)}
+	} else {
+		day, ok := schedules.Lookup(shift.EmployeeTaxID, date)
+		switch {
+		case !ok:
+			deviation.Kind = UnscheduledWork
+			return []Deviation{deviation}
+		case !day.IsWork() && day.Has(ergani.Absent):
+			deviation.Kind = WorkOnAbsentDay
+			return []Deviation{deviation}
+		case !day.IsWork():
+			deviation.Kind = WorkOnRestDay
+			return []Deviation{deviation}
+		}
+
+		scheduledStart, scheduledEnd = matchPeriods(workPeriods(day, date), start, end)
+		if scheduledStart.IsZero() {
+			deviation.Kind = UnscheduledWork
+			return []Deviation{deviation}
+		}
+	}
+
+	deviation.ScheduledStart, deviation.ScheduledEnd = scheduledStart, scheduledEnd
+	early, late := scheduledStart.Sub(start), end.Sub(scheduledEnd)
+	threshold := opts.Tolerance
+	if opts.Model != nil {
+		early, late = modelDeviations(opts, start, end, scheduledStart, scheduledEnd)
+		threshold = 0
+	}
+
+	var deviations []Deviation
+	if early > threshold {
+		d := deviation
+		d.Kind, d.Amount = EarlyArrival, early
+		deviations = append(deviations, d)
+	}
+	if late > threshold {
+		d := deviation
+		d.Kind, d.Amount = LateDeparture, late
+		deviations = append(deviations, d)
+	}
+	return deviations
+}
+
+// modelDeviations returns the early arrival and late departure of a shift that
+// need Overtime under the tolerance model, or zero for permitted movements.
+func modelDeviations(opts Options, start, end, scheduledStart, scheduledEnd time.Time) (early, late time.Duration) {
+	rules := opts.Model.Rules(opts.EmployerTaxID)
+	if deviation, outcome := rules.Check(ergani.Arrival, start, scheduledStart); outcome == tolerance.NeedsOvertime {
+		early = -deviation
+	}
+	if deviation, outcome := rules.Check(ergani.Departure, end, scheduledEnd); outcome == tolerance.NeedsOvertime {
+		late = deviation
+	}
+	return early, late
+}
+
+type period struct {
+	start, end time.Time
+}
+
+// matchPeriods returns the earliest start and latest end of the periods that
+// overlap the shift, or zero times if none does.
+func matchPeriods(periods []period, start, end time.Time) (scheduledStart, scheduledEnd time.Time) {
+	for _, p := range periods {
+		if !p.start.Before(end) || !p.end.After(start) {
+			continue
+		}
+		if scheduledStart.IsZero() || p.start.Before(scheduledStart) {
+			scheduledStart = p.start
+		}
+		if p.end.After(scheduledEnd) {
+			scheduledEnd = p.end
+		}
+	}
+	return scheduledStart, scheduledEnd
+}
+
+func workPeriods(day ergani.ScheduledDay, date time.Time) []period {
+	var periods []period
+	for _, wd := range day.WorkdayDetails {
+		if wd.WorkType.IsWork() {
+			start, end := wd.On(date)
+			periods = append(periods, period{start, end})
+		}
+	}
+	return periods
+}
+
+// CSVHeader is the column layout written by WriteCSV.
+var CSVHeader = []string{
+	"kind", "employee_tax_id", "date", "scheduled_start", "scheduled_end",
+	"actual_start", "actual_end", "deviation_minutes",
+}
+
+// WriteCSV writes the deviations as CSV with the CSVHeader columns. Times are
+// formatted as RFC 3339 and empty when not applicable.
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, d := range r.Deviations {
+		record := []string{
+			string(d.Kind),
+			d.EmployeeTaxID,
+			d.Date.Format("02/01/2006"),
+			formatTime(d.ScheduledStart),
+			formatTime(d.ScheduledEnd),
+			formatTime(d.Shift.Start()),
+			formatTime(d.Shift.End()),
+			strconv.Itoa(int(d.Amount.Minutes())),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,108 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+	"github.com/takispanag/ergani-go-sdk/ergani/tolerance"
+)
+
+func card(taxID string, movement ergani.WorkCardMovementType, at time.Time) ergani.WorkCard {
+	return ergani.WorkCard{EmployeeTaxID: taxID, WorkCardMovementType: movement, WorkCardMovementDateTime: ergani.DateTime{Time: at}}
+}
+
+func TestReconcile(t *testing.T) {
+	monday := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	at := func(day, h, m int) time.Time {
+		return monday.AddDate(0, 0, day).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	index := ergani.NewScheduleIndex()
+	index.AddDaily(ergani.CompanyDailySchedule{EmployeeSchedules: []ergani.EmployeeDailySchedule{
+		{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday}, WorkdayDetails: []ergani.WorkdayDetails{
+			testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00"),
+			testutil.Workday(t, ergani.WorkFromOffice, "17:00", "21:00"),
+		}},
+		{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 1)}, WorkdayDetails: []ergani.WorkdayDetails{
+			testutil.Workday(t, ergani.RestDay, "00:00", "00:00"),
+		}},
+		{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 2)}, WorkdayDetails: []ergani.WorkdayDetails{
+			testutil.Workday(t, ergani.Absent, "00:00", "00:00"),
+		}},
+		{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 3)}, WorkdayDetails: []ergani.WorkdayDetails{
+			testutil.Workday(t, ergani.WorkFromOffice, "22:00", "06:00"),
+		}},
+	}})
+
+	cards := []ergani.WorkCard{
+		// Split shift: within tolerance in the morning, 40 minutes late in the evening.
+		card("111111111", ergani.Arrival, at(0, 8, 50)),
+		card("111111111", ergani.Departure, at(0, 13, 5)),
+		card("111111111", ergani.Arrival, at(0, 17, 0)),
+		card("111111111", ergani.Departure, at(0, 21, 40)),
+		// Rest day and absent day.
+		card("111111111", ergani.Arrival, at(1, 9, 0)),
+		card("111111111", ergani.Departure, at(1, 12, 0)),
+		card("111111111", ergani.Arrival, at(2, 9, 0)),
+		card("111111111", ergani.Departure, at(2, 12, 0)),
+		// Night shift that starts after midnight but belongs to the previous day.
+		card("111111111", ergani.Arrival, at(4, 0, 30)),
+		card("111111111", ergani.Departure, at(4, 6, 0)),
+		// Night shift with an early arrival.
+		card("111111111", ergani.Arrival, at(3, 21, 0)),
+		card("111111111", ergani.Departure, at(4, 0, 0)),
+		// No schedule declared.
+		card("222222222", ergani.Arrival, at(0, 9, 0)),
+		card("222222222", ergani.Departure, at(0, 17, 0)),
+	}
+
+	report := Reconcile(index, cards, Options{Location: time.UTC})
+
+	want := []struct {
+		kind   DeviationKind
+		taxID  string
+		amount time.Duration
+	}{
+		{LateDeparture, "111111111", 40 * time.Minute},
+		{WorkOnRestDay, "111111111", 3 * time.Hour},
+		{WorkOnAbsentDay, "111111111", 3 * time.Hour},
+		{EarlyArrival, "111111111", time.Hour},
+		{UnscheduledWork, "222222222", 8 * time.Hour},
+	}
+	if len(report.Deviations) != len(want) {
+		t.Fatalf("Expected %d deviations, got %+v", len(want), report.Deviations)
+	}
+	for i, w := range want {
+		d := report.Deviations[i]
+		if d.Kind != w.kind || d.EmployeeTaxID != w.taxID || d.Amount != w.amount {
+			t.Errorf("Deviation %d: expected %s of %v for %s, got %s of %v for %s", i, w.kind, w.amount, w.taxID, d.Kind, d.Amount, d.EmployeeTaxID)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != len(want)+1 || records[1][0] != "LATE_DEPARTURE" || records[1][2] != "07/07/2025" ||
+		records[1][4] != "2025-07-07T21:00:00Z" || records[1][7] != "40" {
+		t.Errorf("Unexpected CSV output %v", records)
+	}
+
+	t.Run("ToleranceModel", func(t *testing.T) {
+		model := tolerance.NewModel(tolerance.DefaultRules)
+		model.Employers["123456789"] = tolerance.Rules{EarlyArrival: 15 * time.Minute, LateDeparture: 45 * time.Minute}
+
+		report := Reconcile(index, cards, Options{Location: time.UTC, Model: model, EmployerTaxID: "123456789"})
+		if len(report.Deviations) != len(want)-1 || report.Deviations[0].Kind != WorkOnRestDay {
+			t.Errorf("Expected the late departure to be tolerated, got %+v", report.Deviations)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package ergani
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ReferenceTable identifies one of the Ergani reference (lookup) tables.
+type ReferenceTable string
+
+const (
+	// ActivityCodesTable holds the activity codes (Κ.Α.Δ.).
+	ActivityCodesTable ReferenceTable = "KAD"
+	// SEPEServicesTable holds the labour inspectorate (ΣΕΠΕ) service codes.
+	SEPEServicesTable ReferenceTable = "SEPEServices"
+	// KallikratisTable holds the Kallikratis municipal and community codes.
+	KallikratisTable ReferenceTable = "Kallikratis"
+	// ProfessionsTable holds the profession (specialty) codes used in f_step.
+	ProfessionsTable ReferenceTable = "Professions"
+)
+
+// DefaultReferenceTTL is the default time a cached reference table is considered fresh.
+const DefaultReferenceTTL = 24 * time.Hour
+
+// ReferenceEntry is a single code of a reference table.
+type ReferenceEntry struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	// ParentCode links hierarchical entries, e.g. a community to its municipality.
+	ParentCode string `json:"parentCode,omitempty"`
+}
+
+// ReferenceSource provides reference tables. It is implemented by Client.
+type ReferenceSource interface {
+	Lookup(ctx context.Context, table ReferenceTable) ([]ReferenceEntry, error)
+}
+
+// Lookup retrieves all entries of an Ergani reference table.
+func (c *Client) Lookup(ctx context.Context, table ReferenceTable) ([]ReferenceEntry, error) {
+	endpoint := c.baseURL.JoinPath("/Lookups", url.PathEscape(string(table)))
+	resp, err := c.requestURL(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return []ReferenceEntry{}, resp.Body.Close()
+	}
+
+	var entries []ReferenceEntry
+	decodeErr := json.NewDecoder(resp.Body).Decode(&entries)
+	closeErr := resp.Body.Close()
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode %s lookup: %w", table, decodeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return entries, nil
+}
+
+// referenceSnapshot is a cached reference table as stored in a snapshot.
+type referenceSnapshot struct {
+	FetchedAt time.Time        `json:"fetchedAt"`
+	Entries   []ReferenceEntry `json:"entries"`
+}
+
+// ReferenceCache keeps reference tables in memory and refreshes them from a
+// ReferenceSource once they are older than the TTL. When a refresh fails the stale
+// table is used, so a cache loaded from a snapshot keeps working offline. A nil
+// source makes the cache purely offline. It is safe for concurrent use.
+type ReferenceCache struct {
+	source ReferenceSource
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	tables map[ReferenceTable]referenceSnapshot
+	codes  map[ReferenceTable]map[string]bool
+	// fetches holds the lookups in progress, so that a table is fetched once
+	// however many readers need it.
+	fetches map[ReferenceTable]*referenceFetch
+}
+
+// referenceFetch is a lookup in progress. done is closed when err is set.
+type referenceFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewReferenceCache creates a cache on top of source. A zero ttl defaults to
+// DefaultReferenceTTL.
+func NewReferenceCache(source ReferenceSource, ttl time.Duration) *ReferenceCache {
+	if ttl == 0 {
+		ttl = DefaultReferenceTTL
+	}
+	return &ReferenceCache{
+		source:  source,
+		ttl:     ttl,
+		now:     time.Now,
+		tables:  make(map[ReferenceTable]referenceSnapshot),
+		codes:   make(map[ReferenceTable]map[string]bool),
+		fetches: make(map[ReferenceTable]*referenceFetch),
+	}
+}
+
+// Entries returns a copy of the entries of a table, fetching it when it is missing
+// or expired.
+func (rc *ReferenceCache) Entries(ctx context.Context, table ReferenceTable) ([]ReferenceEntry, error) {
+	if err := rc.refresh(ctx, table); err != nil {
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entries := rc.tables[table].Entries
+	return append([]ReferenceEntry(nil), entries...), nil
+}
+
+// Contains reports whether code exists in the table.
+func (rc *ReferenceCache) Contains(ctx context.Context, table ReferenceTable, code string) (bool, error) {
+	if err := rc.refresh(ctx, table); err != nil {
+		return false, err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.codes[table][code], nil
+}
+
+// refresh makes sure the table is loaded and, if possible, fresh. The lookup runs
+// without holding mu, and only one lookup per table is in progress at a time.
+// Readers of a stale table do not wait for it; readers of a missing table do, and
+// start a lookup of their own if the one they waited for was cancelled by its
+// caller.
+func (rc *ReferenceCache) refresh(ctx context.Context, table ReferenceTable) error {
+	for {
+		rc.mu.Lock()
+		cached, ok := rc.tables[table]
+		if ok && (rc.source == nil || rc.now().Sub(cached.FetchedAt) < rc.ttl) {
+			rc.mu.Unlock()
+			return nil
+		}
+		if rc.source == nil {
+			rc.mu.Unlock()
+			return fmt.Errorf("reference table %s is not available offline", table)
+		}
+
+		fetch, inProgress := rc.fetches[table]
+		if !inProgress {
+			fetch = &referenceFetch{done: make(chan struct{})}
+			rc.fetches[table] = fetch
+			rc.mu.Unlock()
+			return rc.fetch(ctx, table, fetch, ok)
+		}
+		rc.mu.Unlock()
+
+		if ok {
+			// Use the stale table while another reader refreshes it.
+			return nil
+		}
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if fetch.err == nil {
+			return nil
+		}
+		if errors.Is(fetch.err, context.Canceled) || errors.Is(fetch.err, context.DeadlineExceeded) {
+			continue
+		}
+		return fmt.Errorf("failed to load reference table %s: %w", table, fetch.err)
+	}
+}
+
+// fetch looks up a table for refresh and completes the fetch, also when the
+// source panics. stale reports whether an expired table can be used instead.
+func (rc *ReferenceCache) fetch(ctx context.Context, table ReferenceTable, fetch *referenceFetch, stale bool) error {
+	var entries []ReferenceEntry
+	err := fmt.Errorf("lookup of reference table %s did not complete", table)
+	defer func() {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		if err == nil {
+			rc.store(table, referenceSnapshot{FetchedAt: rc.now(), Entries: entries})
+		}
+		fetch.err = err
+		delete(rc.fetches, table)
+		close(fetch.done)
+	}()
+
+	entries, err = rc.source.Lookup(ctx, table)
+	if err != nil {
+		if stale {
+			// Fall back to the stale table rather than blocking validation.
+			return nil
+		}
+		return fmt.Errorf("failed to load reference table %s: %w", table, err)
+	}
+	return nil
+}
+
+// store replaces a table and its code index. It must be called with mu held.
+func (rc *ReferenceCache) store(table ReferenceTable, snapshot referenceSnapshot) {
+	codes := make(map[string]bool, len(snapshot.Entries))
+	for _, e := range snapshot.Entries {
+		codes[e.Code] = true
+	}
+	rc.tables[table] = snapshot
+	rc.codes[table] = codes
+}
+
+// WriteSnapshot writes all cached tables as JSON, e.g. to ship them with an
+// application that has to validate documents offline.
+func (rc *ReferenceCache) WriteSnapshot(w io.Writer) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if err := json.NewEncoder(w).Encode(rc.tables); err != nil {
+		return fmt.Errorf("failed to write reference snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot loads tables written by WriteSnapshot, replacing the cached ones.
+// Loaded tables keep the time they were originally fetched at.
+func (rc *ReferenceCache) LoadSnapshot(r io.Reader) error {
+	var tables map[ReferenceTable]referenceSnapshot
+	if err := json.NewDecoder(r).Decode(&tables); err != nil {
+		return fmt.Errorf("failed to read reference snapshot: %w", err)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for table, snapshot := range tables {
+		rc.store(table, snapshot)
+	}
+	return nil
+}
+
+// referenceCheck is a code of a document field that must exist in a reference table.
+type referenceCheck struct {
+	field string
+	table ReferenceTable
+	code  string
+}
+
+// ValidateOvertime checks the SEPE service, activity, Kallikratis and profession
+// codes of an overtime document against the reference tables. It returns a
+// *ValidationError for the first unknown code.
+func (rc *ReferenceCache) ValidateOvertime(ctx context.Context, o CompanyOvertime) error {
+	checks := []referenceCheck{
+		{"SEPEServiceCode", SEPEServicesTable, o.SEPEServiceCode},
+		{"PrimaryActivityCode", ActivityCodesTable, o.PrimaryActivityCode},
+		{"BranchActivityCode", ActivityCodesTable, o.BranchActivityCode},
+		{"SecondaryActivityCode1", ActivityCodesTable, o.SecondaryActivityCode1},
+		{"SecondaryActivityCode2", ActivityCodesTable, o.SecondaryActivityCode2},
+		{"SecondaryActivityCode3", ActivityCodesTable, o.SecondaryActivityCode3},
+		{"SecondaryActivityCode4", ActivityCodesTable, o.SecondaryActivityCode4},
+		{"KallikratisCode", KallikratisTable, o.KallikratisCode},
+	}
+	for _, row := range o.EmployeeOvertimes {
+		checks = append(checks, referenceCheck{"EmployeeProfessionCode", ProfessionsTable, row.EmployeeProfessionCode})
+	}
+
+	for _, check := range checks {
+		if check.code == "" {
+			continue
+		}
+		ok, err := rc.Contains(ctx, check.table, check.code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &ValidationError{
+				Field:   check.field,
+				Message: fmt.Sprintf("unknown %s code %q", check.table, check.code),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package ergani
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeReferenceSource struct {
+	calls  int
+	err    error
+	tables map[ReferenceTable][]ReferenceEntry
+}
+
+func (f *fakeReferenceSource) Lookup(_ context.Context, table ReferenceTable) ([]ReferenceEntry, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.tables[table], nil
+}
+
+func newFakeReferenceSource() *fakeReferenceSource {
+	return &fakeReferenceSource{tables: map[ReferenceTable][]ReferenceEntry{
+		SEPEServicesTable:  {{Code: "10000", Description: "ΣΕΠΕ ΑΘΗΝΩΝ"}},
+		ActivityCodesTable: {{Code: "5610", Description: "ΕΣΤΙΑΤΟΡΙΑ"}},
+		KallikratisTable:   {{Code: "9186", Description: "ΑΘΗΝΑ"}},
+		ProfessionsTable:   {{Code: "512001", Description: "ΜΑΓΕΙΡΑΣ"}},
+	}}
+}
+
+func TestReferenceCache_TTL(t *testing.T) {
+	source := newFakeReferenceSource()
+	cache := NewReferenceCache(source, time.Hour)
+	now := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		ok, err := cache.Contains(ctx, ActivityCodesTable, "5610")
+		if err != nil || !ok {
+			t.Fatalf("Expected code 5610 to exist, got %v, %v", ok, err)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("Expected 1 lookup within the TTL, got %d", source.calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	source.err = errors.New("ergani is down")
+	ok, err := cache.Contains(ctx, ActivityCodesTable, "5610")
+	if err != nil || !ok {
+		t.Errorf("Expected the stale table to be used when the refresh fails, got %v, %v", ok, err)
+	}
+	if source.calls != 2 {
+		t.Errorf("Expected a refresh after the TTL, got %d lookups", source.calls)
+	}
+}
+
+// blockingReferenceSource blocks every lookup until release is closed.
+type blockingReferenceSource struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingReferenceSource) Lookup(context.Context, ReferenceTable) ([]ReferenceEntry, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return []ReferenceEntry{{Code: "5610"}, {Code: "5630"}}, nil
+}
+
+func TestReferenceCache_Refresh(t *testing.T) {
+	source := &blockingReferenceSource{started: make(chan struct{}, 1), release: make(chan struct{})}
+	cache := NewReferenceCache(source, time.Hour)
+	now := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	cache.store(ActivityCodesTable, referenceSnapshot{FetchedAt: now.Add(-2 * time.Hour), Entries: []ReferenceEntry{{Code: "5610"}}})
+
+	ctx := context.Background()
+	refreshed := make(chan error)
+	go func() {
+		_, err := cache.Entries(ctx, ActivityCodesTable)
+		refreshed <- err
+	}()
+	<-source.started
+
+	// The refresh is in progress: readers use the stale table without waiting.
+	if ok, err := cache.Contains(ctx, ActivityCodesTable, "5610"); err != nil || !ok {
+		t.Errorf("Expected the stale table during the refresh, got %v, %v", ok, err)
+	}
+	if ok, _ := cache.Contains(ctx, ActivityCodesTable, "5630"); ok {
+		t.Error("Expected the refreshed code to be unknown before the refresh completes")
+	}
+
+	close(source.release)
+	if err := <-refreshed; err != nil {
+		t.Fatalf("Unexpected refresh error: %v", err)
+	}
+
+	entries, _ := cache.Entries(ctx, ActivityCodesTable)
+	entries[0].Code = "changed"
+	if ok, _ := cache.Contains(ctx, ActivityCodesTable, "5630"); !ok {
+		t.Error("Expected the refreshed code to be known")
+	}
+	if again, _ := cache.Entries(ctx, ActivityCodesTable); again[0].Code != "5610" {
+		t.Errorf("Expected Entries to return a copy, got %v", again)
+	}
+}
+
+func TestReferenceCache_Snapshot(t *testing.T) {
+	online := NewReferenceCache(newFakeReferenceSource(), 0)
+	if _, err := online.Entries(context.Background(), ProfessionsTable); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := online.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	offline := NewReferenceCache(nil, 0)
+	if err := offline.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+
+	ok, err := offline.Contains(context.Background(), ProfessionsTable, "512001")
+	if err != nil || !ok {
+		t.Errorf("Expected profession 512001 in the offline snapshot, got %v, %v", ok, err)
+	}
+	if _, err := offline.Contains(context.Background(), KallikratisTable, "9186"); err == nil {
+		t.Error("Expected an error for a table missing from the offline snapshot")
+	}
+}
+
+func TestReferenceCache_ValidateOvertime(t *testing.T) {
+	cache := NewReferenceCache(newFakeReferenceSource(), 0)
+
+	overtime := CompanyOvertime{
+		SEPEServiceCode:     "10000",
+		PrimaryActivityCode: "5610",
+		BranchActivityCode:  "5610",
+		KallikratisCode:     "9186",
+		EmployeeOvertimes:   []Overtime{{EmployeeProfessionCode: "512001"}},
+	}
+	if err := cache.ValidateOvertime(context.Background(), overtime); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	overtime.EmployeeOvertimes[0].EmployeeProfessionCode = "999999"
+	err := cache.ValidateOvertime(context.Background(), overtime)
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected error to be of type ValidationError, but got %T", err)
+	}
+	if validationErr.Field != "EmployeeProfessionCode" {
+		t.Errorf("Expected the profession code to be reported, got %s", validationErr.Field)
+	}
+}
+
+// flakyReferenceSource fails its first lookup, by panicking or by waiting for the
+// caller to give up, and succeeds afterwards.
+type flakyReferenceSource struct {
+	panics  bool
+	started chan struct{}
+	calls   int32
+}
+
+func (f *flakyReferenceSource) Lookup(ctx context.Context, _ ReferenceTable) ([]ReferenceEntry, error) {
+	if atomic.AddInt32(&f.calls, 1) > 1 {
+		return []ReferenceEntry{{Code: "5610"}}, nil
+	}
+	close(f.started)
+	if f.panics {
+		time.Sleep(20 * time.Millisecond)
+		panic("lookup failed")
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestReferenceCache_FailedFetch(t *testing.T) {
+	for _, panics := range []bool{true, false} {
+		source := &flakyReferenceSource{panics: panics, started: make(chan struct{})}
+		cache := NewReferenceCache(source, time.Hour)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			defer func() { recover() }()
+			cache.Entries(ctx, ActivityCodesTable)
+		}()
+		<-source.started
+
+		waited := make(chan error, 1)
+		go func() {
+			_, err := cache.Entries(context.Background(), ActivityCodesTable)
+			waited <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case err := <-waited:
+			if !panics && err != nil {
+				t.Errorf("Expected the waiting reader to fetch the table itself, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Panics %v: expected the waiting reader not to block", panics)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ergani
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// RosterDiscrepancyKind defines the kind of difference between the Ergani roster
+// and a local (HR) roster.
+type RosterDiscrepancyKind string
+
+const (
+	// MissingFromErgani signifies a local employee that is not registered in Ergani.
+	MissingFromErgani RosterDiscrepancyKind = "MISSING_FROM_ERGANI"
+	// MissingFromRoster signifies a registered employee that is not in the local roster.
+	MissingFromRoster RosterDiscrepancyKind = "MISSING_FROM_ROSTER"
+	// NameMismatch signifies that the first or last name differs.
+	NameMismatch RosterDiscrepancyKind = "NAME_MISMATCH"
+	// TaxIDMismatch signifies that the employee was matched by SSN but the tax ID differs.
+	TaxIDMismatch RosterDiscrepancyKind = "TAX_ID_MISMATCH"
+	// BranchMismatch signifies that the employee is registered in a different branch.
+	BranchMismatch RosterDiscrepancyKind = "BRANCH_MISMATCH"
+)
+
+// RosterDiscrepancy is a single difference found by DiffRoster. Registered or Local
+// is nil when the employee is missing from that side.
+type RosterDiscrepancy struct {
+	Kind       RosterDiscrepancyKind
+	Registered *RegisteredEmployee
+	Local      *RegisteredEmployee
+}
+
+// String describes the discrepancy.
+func (d RosterDiscrepancy) String() string {
+	switch d.Kind {
+	case MissingFromErgani:
+		return fmt.Sprintf("%s: employee %s is not registered in Ergani", d.Kind, d.Local.EmployeeTaxID)
+	case MissingFromRoster:
+		return fmt.Sprintf("%s: registered employee %s is not in the local roster", d.Kind, d.Registered.EmployeeTaxID)
+	case NameMismatch:
+		return fmt.Sprintf("%s: employee %s is registered as %s %s, not %s %s", d.Kind, d.Registered.EmployeeTaxID,
+			d.Registered.EmployeeLastName, d.Registered.EmployeeFirstName, d.Local.EmployeeLastName, d.Local.EmployeeFirstName)
+	case TaxIDMismatch:
+		return fmt.Sprintf("%s: employee with SSN %s is registered with tax ID %s, not %s", d.Kind, d.Registered.EmployeeSSN,
+			d.Registered.EmployeeTaxID, d.Local.EmployeeTaxID)
+	case BranchMismatch:
+		return fmt.Sprintf("%s: employee %s is registered in branch %d, not %d", d.Kind, d.Registered.EmployeeTaxID,
+			d.Registered.BusinessBranchNumber, d.Local.BusinessBranchNumber)
+	default:
+		return string(d.Kind)
+	}
+}
+
+// ListEmployees retrieves the employees registered in Ergani for a business branch.
+func (c *Client) ListEmployees(ctx context.Context, businessBranchNumber int) ([]RegisteredEmployee, error) {
+	endpoint := c.baseURL.JoinPath("/Employer/Branches", strconv.Itoa(businessBranchNumber), "Employees")
+	resp, err := c.requestURL(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return []RegisteredEmployee{}, resp.Body.Close()
+	}
+
+	var employees []RegisteredEmployee
+	decodeErr := json.NewDecoder(resp.Body).Decode(&employees)
+	closeErr := resp.Body.Close()
+
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode employees: %w", decodeErr)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	return employees, nil
+}
+
+// DiffRoster compares the roster registered in Ergani with a local roster.
+// Employees are matched by tax ID, or by SSN when the tax ID is not found. Names are
+// compared exactly, including surrounding spaces, because Ergani rejects documents
+// whose names do not match the registration. Discrepancies are reported in the order of
+// the local roster, followed by the registered employees missing from it.
+func DiffRoster(registered, local []RegisteredEmployee) []RosterDiscrepancy {
+	byTaxID := make(map[string]int, len(registered))
+	bySSN := make(map[string]int, len(registered))
+	for i, r := range registered {
+		byTaxID[r.EmployeeTaxID] = i
+		if r.EmployeeSSN != "" {
+			bySSN[r.EmployeeSSN] = i
+		}
+	}
+
+	var discrepancies []RosterDiscrepancy
+	matched := make([]bool, len(registered))
+	for i := range local {
+		l := &local[i]
+
+		ri, ok := byTaxID[l.EmployeeTaxID]
+		if !ok && l.EmployeeSSN != "" {
+			if ri, ok = bySSN[l.EmployeeSSN]; ok {
+				discrepancies = append(discrepancies, RosterDiscrepancy{Kind: TaxIDMismatch, Registered: &registered[ri], Local: l})
+			}
+		}
+		if !ok {
+			discrepancies = append(discrepancies, RosterDiscrepancy{Kind: MissingFromErgani, Local: l})
+			continue
+		}
+
+		matched[ri] = true
+		r := &registered[ri]
+		if r.EmployeeLastName != l.EmployeeLastName || r.EmployeeFirstName != l.EmployeeFirstName {
+			discrepancies = append(discrepancies, RosterDiscrepancy{Kind: NameMismatch, Registered: r, Local: l})
+		}
+		if r.BusinessBranchNumber != l.BusinessBranchNumber {
+			discrepancies = append(discrepancies, RosterDiscrepancy{Kind: BranchMismatch, Registered: r, Local: l})
+		}
+	}
+
+	for i := range registered {
+		if !matched[i] {
+			discrepancies = append(discrepancies, RosterDiscrepancy{Kind: MissingFromRoster, Registered: &registered[i]})
+		}
+	}
+
+	return discrepancies
+}
+
+// ValidateWorkCards checks every work card entry against the registered roster and
+// returns a *ValidationError for the first employee that is not registered in the
+// card's branch or whose name differs from the registration. Names are compared
+// exactly, since they are submitted as they are.
+func ValidateWorkCards(registered []RegisteredEmployee, companyWorkCards []CompanyWorkCard) error {
+	byTaxID := make(map[string]RegisteredEmployee, len(registered))
+	for _, r := range registered {
+		byTaxID[r.EmployeeTaxID] = r
+	}
+
+	for _, cwc := range companyWorkCards {
+		for _, wc := range cwc.CardDetails {
+			r, ok := byTaxID[wc.EmployeeTaxID]
+			if !ok {
+				return &ValidationError{Field: "EmployeeTaxID", Message: fmt.Sprintf("employee %s is not registered in Ergani", wc.EmployeeTaxID)}
+			}
+			if r.BusinessBranchNumber != cwc.BusinessBranchNumber {
+				return &ValidationError{
+					Field:   "BusinessBranchNumber",
+					Message: fmt.Sprintf("employee %s is registered in branch %d, not %d", wc.EmployeeTaxID, r.BusinessBranchNumber, cwc.BusinessBranchNumber),
+				}
+			}
+			if r.EmployeeLastName != wc.EmployeeLastName || r.EmployeeFirstName != wc.EmployeeFirstName {
+				return &ValidationError{
+					Field:   "EmployeeLastName",
+					Message: fmt.Sprintf("employee %s is registered as %s %s", wc.EmployeeTaxID, r.EmployeeLastName, r.EmployeeFirstName),
+				}
+			}
+		}
+	}
+	return nil
+}
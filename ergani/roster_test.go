@@ -0,0 +1,74 @@
+package ergani
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDiffRoster(t *testing.T) {
+	registered := []RegisteredEmployee{
+		{EmployeeTaxID: "111111111", EmployeeSSN: "01", EmployeeLastName: "ΠΑΠΑΔΟΠΟΥΛΟΣ", EmployeeFirstName: "ΓΙΩΡΓΟΣ", BusinessBranchNumber: 0},
+		{EmployeeTaxID: "222222222", EmployeeSSN: "02", EmployeeLastName: "ΒΑΣΙΛΕΙΟΥ", EmployeeFirstName: "ΜΑΡΙΑ", BusinessBranchNumber: 1},
+		{EmployeeTaxID: "333333333", EmployeeSSN: "03", EmployeeLastName: "ΝΙΚΟΛΑΟΥ", EmployeeFirstName: "ΕΛΕΝΗ", BusinessBranchNumber: 0},
+		{EmployeeTaxID: "444444444", EmployeeSSN: "04", EmployeeLastName: "ΚΩΣΤΑΣ", EmployeeFirstName: "ΝΙΚΟΣ", BusinessBranchNumber: 0},
+	}
+	local := []RegisteredEmployee{
+		{EmployeeTaxID: "111111111", EmployeeSSN: "01", EmployeeLastName: "ΠΑΠΑΔΟΠΟΥΛΟΣ ", EmployeeFirstName: "ΓΙΩΡΓΟΣ", BusinessBranchNumber: 0},
+		{EmployeeTaxID: "222222222", EmployeeSSN: "02", EmployeeLastName: "ΒΑΣΙΛΕΙΟΥ", EmployeeFirstName: "ΜΑΙΡΗ", BusinessBranchNumber: 0},
+		{EmployeeTaxID: "333333399", EmployeeSSN: "03", EmployeeLastName: "ΝΙΚΟΛΑΟΥ", EmployeeFirstName: "ΕΛΕΝΗ", BusinessBranchNumber: 0},
+		{EmployeeTaxID: "555555555", EmployeeSSN: "05", EmployeeLastName: "ΔΗΜΟΥ", EmployeeFirstName: "ΑΝΝΑ", BusinessBranchNumber: 0},
+	}
+
+	discrepancies := DiffRoster(registered, local)
+
+	// The trailing space of the first local name is a mismatch, as Ergani would reject it.
+	expected := []RosterDiscrepancyKind{NameMismatch, NameMismatch, BranchMismatch, TaxIDMismatch, MissingFromErgani, MissingFromRoster}
+	if len(discrepancies) != len(expected) {
+		t.Fatalf("Expected %d discrepancies, got %d: %v", len(expected), len(discrepancies), discrepancies)
+	}
+	for i, kind := range expected {
+		if discrepancies[i].Kind != kind {
+			t.Errorf("Expected discrepancy %d to be %s, got %s", i, kind, discrepancies[i].Kind)
+		}
+	}
+	if discrepancies[5].Registered.EmployeeTaxID != "444444444" {
+		t.Errorf("Expected 444444444 to be missing from the roster, got %s", discrepancies[5].Registered.EmployeeTaxID)
+	}
+}
+
+func TestValidateWorkCards(t *testing.T) {
+	registered := []RegisteredEmployee{
+		{EmployeeTaxID: "123456789", EmployeeLastName: "Doe", EmployeeFirstName: "John", BusinessBranchNumber: 1},
+	}
+	cards := []CompanyWorkCard{
+		{BusinessBranchNumber: 1, CardDetails: []WorkCard{{EmployeeTaxID: "123456789", EmployeeLastName: "Doe", EmployeeFirstName: "John"}}},
+	}
+	if err := ValidateWorkCards(registered, cards); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	cards[0].CardDetails[0].EmployeeFirstName = "Jon"
+	if _, ok := ValidateWorkCards(registered, cards).(*ValidationError); !ok {
+		t.Error("Expected a ValidationError for a mismatching name")
+	}
+
+	cards[0].CardDetails[0].EmployeeFirstName = "John "
+	if _, ok := ValidateWorkCards(registered, cards).(*ValidationError); !ok {
+		t.Error("Expected a ValidationError for a name with a trailing space")
+	}
+}
+
+func TestListEmployees(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	client, _ := NewClient("testuser", "testpass", server.URL)
+
+	employees, err := client.ListEmployees(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error on ListEmployees, but got: %v", err)
+	}
+	if len(employees) != 1 || employees[0].EmployeeLastName != "ΠΑΠΑΔΟΠΟΥΛΟΣ" {
+		t.Errorf("Unexpected employees %+v", employees)
+	}
+}
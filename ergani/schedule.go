@@ -0,0 +1,114 @@
+package ergani
+
+import (
+	"time"
+)
+
+// ScheduledDay is the schedule declared for an employee on a date.
+type ScheduledDay struct {
+	EmployeeTaxID  string
+	Date           Date
+	WorkdayDetails []WorkdayDetails
+	// Source is the document type that declared the day, i.e. DailyScheduleDocument
+	// or WeeklyScheduleDocument.
+	Source DocumentType
+}
+
+// IsWork reports whether any working period is declared for the day.
+func (d ScheduledDay) IsWork() bool {
+	for _, wd := range d.WorkdayDetails {
+		if wd.WorkType.IsWork() {
+			return true
+		}
+	}
+	return false
+}
+
+// Has reports whether a period of the given work type is declared for the day.
+func (d ScheduledDay) Has(t ScheduleWorkType) bool {
+	for _, wd := range d.WorkdayDetails {
+		if wd.WorkType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// On returns the start and end of the working period on the given date, in the
+// date's location. A period that ends on or before its start ends on the next day.
+func (wd WorkdayDetails) On(date time.Time) (start, end time.Time) {
+	y, m, d := date.Date()
+	loc := date.Location()
+	start = time.Date(y, m, d, wd.StartTime.Hour(), wd.StartTime.Minute(), 0, 0, loc)
+	return start, start.Add(wd.Duration())
+}
+
+type scheduleKey struct {
+	taxID string
+	day   string
+}
+
+func newScheduleKey(taxID string, date time.Time) scheduleKey {
+	return scheduleKey{taxID: taxID, day: date.Format("2006-01-02")}
+}
+
+// ScheduleIndex resolves the schedule declared for an employee on a date from
+// daily and weekly declarations. A daily schedule takes precedence over a weekly
+// one; among declarations of the same kind the one added last wins.
+type ScheduleIndex struct {
+	daily  map[scheduleKey][]WorkdayDetails
+	weekly []CompanyWeeklySchedule
+}
+
+// NewScheduleIndex creates an empty ScheduleIndex.
+func NewScheduleIndex() *ScheduleIndex {
+	return &ScheduleIndex{daily: make(map[scheduleKey][]WorkdayDetails)}
+}
+
+// AddDaily adds the days declared by a daily schedule, replacing earlier daily
+// declarations of the same employees and dates.
+func (x *ScheduleIndex) AddDaily(schedule CompanyDailySchedule) {
+	days := make(map[scheduleKey][]WorkdayDetails)
+	for _, es := range schedule.EmployeeSchedules {
+		key := newScheduleKey(es.EmployeeTaxID, es.ScheduleDate.Time)
+		days[key] = append(days[key], es.WorkdayDetails...)
+	}
+	for key, details := range days {
+		x.daily[key] = details
+	}
+}
+
+// AddWeekly adds a weekly schedule, which applies between its StartDate and EndDate.
+func (x *ScheduleIndex) AddWeekly(schedule CompanyWeeklySchedule) {
+	x.weekly = append(x.weekly, schedule)
+}
+
+// Lookup returns the schedule declared for the employee on the given date.
+func (x *ScheduleIndex) Lookup(taxID string, date time.Time) (ScheduledDay, bool) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if details, ok := x.daily[newScheduleKey(taxID, day)]; ok {
+		return ScheduledDay{EmployeeTaxID: taxID, Date: Date{Time: day}, WorkdayDetails: details, Source: DailyScheduleDocument}, true
+	}
+
+	key := day.Format("2006-01-02")
+	for i := len(x.weekly) - 1; i >= 0; i-- {
+		schedule := x.weekly[i]
+		if key < schedule.StartDate.Format("2006-01-02") || key > schedule.EndDate.Format("2006-01-02") {
+			continue
+		}
+
+		var details []WorkdayDetails
+		found := false
+		for _, es := range schedule.EmployeeSchedules {
+			if es.EmployeeTaxID == taxID && es.ScheduleDay.Weekday == day.Weekday() {
+				details = append(details, es.WorkdayDetails...)
+				found = true
+			}
+		}
+		if found {
+			return ScheduledDay{EmployeeTaxID: taxID, Date: Date{Time: day}, WorkdayDetails: details, Source: WeeklyScheduleDocument}, true
+		}
+	}
+
+	return ScheduledDay{}, false
+}
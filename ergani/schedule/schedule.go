@@ -0,0 +1,236 @@
+// Package schedule converts between weekly (WTOWeek) and daily (WTODaily) work
+// time declarations.
+//
+// Expand turns a weekly template into concrete days, applying holidays and per-day
+// exceptions. Consolidate collapses repeated daily entries into a weekly template
+// plus the days that differ from it. Both keep every WorkdayDetails period, so split
+// shifts survive the conversion.
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// ExpandOptions configures Expand.
+type ExpandOptions struct {
+	// From and To narrow the expansion to a part of the weekly validity range.
+	// Zero values keep the weekly StartDate and EndDate.
+	From time.Time
+	To   time.Time
+	// IsHoliday, if set, reports public holidays, e.g. backed by the holidays package.
+	IsHoliday func(ergani.Date) bool
+	// HolidayWorkType is declared for employees on holidays. Defaults to RestDay.
+	HolidayWorkType ergani.ScheduleWorkType
+	// Exceptions replace the expanded days of the same employee and date.
+	Exceptions []ergani.EmployeeDailySchedule
+}
+
+// Expand converts a weekly schedule into a daily schedule with one entry per
+// employee and date of the validity range on which the weekly schedule declares
+// the employee, plus the exceptions of employees it does not declare that day.
+// Weekdays without a weekly entry are left out rather than declared as rest days.
+// The header fields are copied from the weekly schedule.
+func Expand(weekly ergani.CompanyWeeklySchedule, opts ExpandOptions) (ergani.CompanyDailySchedule, error) {
+	start, end := day(weekly.StartDate.Time), day(weekly.EndDate.Time)
+	if !opts.From.IsZero() && day(opts.From).After(start) {
+		start = day(opts.From)
+	}
+	if !opts.To.IsZero() && day(opts.To).Before(end) {
+		end = day(opts.To)
+	}
+	if end.Before(start) {
+		return ergani.CompanyDailySchedule{}, &ergani.ValidationError{Field: "To", Message: "expansion range is outside the weekly validity range"}
+	}
+	if opts.HolidayWorkType == "" {
+		opts.HolidayWorkType = ergani.RestDay
+	}
+
+	exceptions := make(map[string]ergani.EmployeeDailySchedule, len(opts.Exceptions))
+	for _, es := range opts.Exceptions {
+		exceptions[key(es.EmployeeTaxID, es.ScheduleDate.Time)] = es
+	}
+
+	startDate, endDate := ergani.Date{Time: start}, ergani.Date{Time: end}
+	daily := ergani.CompanyDailySchedule{
+		BusinessBranchNumber: weekly.BusinessBranchNumber,
+		StartDate:            &startDate,
+		EndDate:              &endDate,
+		Comments:             weekly.Comments,
+	}
+
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		d := ergani.Date{Time: date}
+		holiday := opts.IsHoliday != nil && opts.IsHoliday(d)
+
+		// Several rows of the same employee and weekday are merged into one day.
+		var order []string
+		rows := make(map[string]ergani.EmployeeDailySchedule)
+		for _, es := range weekly.EmployeeSchedules {
+			if es.ScheduleDay.Weekday != date.Weekday() {
+				continue
+			}
+			row, ok := rows[es.EmployeeTaxID]
+			if !ok {
+				order = append(order, es.EmployeeTaxID)
+				row = ergani.EmployeeDailySchedule{
+					EmployeeTaxID:     es.EmployeeTaxID,
+					EmployeeLastName:  es.EmployeeLastName,
+					EmployeeFirstName: es.EmployeeFirstName,
+					ScheduleDate:      d,
+				}
+			}
+			row.WorkdayDetails = append(row.WorkdayDetails, es.WorkdayDetails...)
+			rows[es.EmployeeTaxID] = row
+		}
+
+		for _, taxID := range order {
+			row := rows[taxID]
+			if exception, ok := exceptions[key(taxID, date)]; ok {
+				row.WorkdayDetails = exception.WorkdayDetails
+			} else if holiday {
+				row.WorkdayDetails = []ergani.WorkdayDetails{{WorkType: opts.HolidayWorkType}}
+			}
+			daily.EmployeeSchedules = append(daily.EmployeeSchedules, row)
+		}
+		for _, es := range opts.Exceptions {
+			if sameDay(es.ScheduleDate.Time, date) {
+				if _, ok := rows[es.EmployeeTaxID]; !ok {
+					daily.EmployeeSchedules = append(daily.EmployeeSchedules, es)
+				}
+			}
+		}
+	}
+	return daily, nil
+}
+
+// Consolidate collapses a daily schedule into a weekly schedule and the days that
+// differ from it. For every employee and weekday the most frequent declaration
+// becomes the weekly entry; ties go to declarations with work, so that e.g. a
+// holiday declared as a rest day does not replace the regular shift, and then to
+// the earliest date. The validity range is the StartDate and EndDate of the daily
+// schedule or, if unset, its first and last date. Days the daily schedule does not
+// declare take the weekly entry.
+func Consolidate(daily ergani.CompanyDailySchedule) (ergani.CompanyWeeklySchedule, ergani.CompanyDailySchedule, error) {
+	if len(daily.EmployeeSchedules) == 0 {
+		return ergani.CompanyWeeklySchedule{}, ergani.CompanyDailySchedule{}, &ergani.ValidationError{Field: "EmployeeSchedules", Message: "daily schedule is empty"}
+	}
+
+	rows := make([]ergani.EmployeeDailySchedule, len(daily.EmployeeSchedules))
+	copy(rows, daily.EmployeeSchedules)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].ScheduleDate.Before(rows[j].ScheduleDate.Time) })
+
+	start, end := day(rows[0].ScheduleDate.Time), day(rows[len(rows)-1].ScheduleDate.Time)
+	if daily.StartDate != nil {
+		start = day(daily.StartDate.Time)
+	}
+	if daily.EndDate != nil {
+		end = day(daily.EndDate.Time)
+	}
+
+	type slot struct {
+		taxID   string
+		weekday time.Weekday
+	}
+	type candidate struct {
+		row   ergani.EmployeeDailySchedule
+		count int
+	}
+	var slots []slot
+	candidates := make(map[slot][]candidate)
+	for _, row := range rows {
+		s := slot{row.EmployeeTaxID, row.ScheduleDate.Weekday()}
+		if _, ok := candidates[s]; !ok {
+			slots = append(slots, s)
+		}
+		found := false
+		for i, c := range candidates[s] {
+			if sameDetails(c.row.WorkdayDetails, row.WorkdayDetails) {
+				candidates[s][i].count++
+				found = true
+				break
+			}
+		}
+		if !found {
+			candidates[s] = append(candidates[s], candidate{row: row, count: 1})
+		}
+	}
+
+	weekly := ergani.CompanyWeeklySchedule{
+		BusinessBranchNumber: daily.BusinessBranchNumber,
+		StartDate:            ergani.Date{Time: start},
+		EndDate:              ergani.Date{Time: end},
+		Comments:             daily.Comments,
+	}
+	exceptions := daily
+	exceptions.EmployeeSchedules = nil
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].taxID != slots[j].taxID {
+			return slots[i].taxID < slots[j].taxID
+		}
+		return (slots[i].weekday+6)%7 < (slots[j].weekday+6)%7
+	})
+	template := make(map[slot][]ergani.WorkdayDetails)
+	for _, s := range slots {
+		best := candidates[s][0]
+		for _, c := range candidates[s][1:] {
+			if c.count > best.count || (c.count == best.count && working(c.row.WorkdayDetails) && !working(best.row.WorkdayDetails)) {
+				best = c
+			}
+		}
+		template[s] = best.row.WorkdayDetails
+		weekly.EmployeeSchedules = append(weekly.EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+			EmployeeTaxID:     best.row.EmployeeTaxID,
+			EmployeeLastName:  best.row.EmployeeLastName,
+			EmployeeFirstName: best.row.EmployeeFirstName,
+			ScheduleDay:       ergani.Weekday{Weekday: s.weekday},
+			WorkdayDetails:    best.row.WorkdayDetails,
+		})
+	}
+
+	for _, row := range rows {
+		if !sameDetails(template[slot{row.EmployeeTaxID, row.ScheduleDate.Weekday()}], row.WorkdayDetails) {
+			exceptions.EmployeeSchedules = append(exceptions.EmployeeSchedules, row)
+		}
+	}
+	return weekly, exceptions, nil
+}
+
+// working reports whether a declaration contains work.
+func working(details []ergani.WorkdayDetails) bool {
+	for _, wd := range details {
+		if wd.WorkType.IsWork() {
+			return true
+		}
+	}
+	return false
+}
+
+func sameDetails(a, b []ergani.WorkdayDetails) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].WorkType != b[i].WorkType ||
+			a[i].StartTime.Format("15:04") != b[i].StartTime.Format("15:04") ||
+			a[i].EndTime.Format("15:04") != b[i].EndTime.Format("15:04") {
+			return false
+		}
+	}
+	return true
+}
+
+func key(taxID string, date time.Time) string {
+	return taxID + "/" + date.Format("2006-01-02")
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+func TestExpandAndConsolidate(t *testing.T) {
+	monday := time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC)
+	split := []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00"), testutil.Workday(t, ergani.WorkFromOffice, "17:00", "21:00")}
+	rest := []ergani.WorkdayDetails{{WorkType: ergani.RestDay}}
+
+	weekly := ergani.CompanyWeeklySchedule{
+		BusinessBranchNumber: 1,
+		StartDate:            ergani.Date{Time: monday},
+		EndDate:              ergani.Date{Time: monday.AddDate(0, 0, 13)},
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		details := split
+		if d == time.Sunday || d == time.Saturday {
+			details = rest
+		}
+		weekly.EmployeeSchedules = append(weekly.EmployeeSchedules, ergani.EmployeeWeeklySchedule{
+			EmployeeTaxID:  "111111111",
+			ScheduleDay:    ergani.Weekday{Weekday: d},
+			WorkdayDetails: details,
+		})
+	}
+
+	// Friday the 15th is a holiday and Thursday the 21st is a short day.
+	holiday := ergani.Date{Time: monday.AddDate(0, 0, 4)}
+	short := ergani.EmployeeDailySchedule{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 10)}, WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00")}}
+
+	daily, err := Expand(weekly, ExpandOptions{
+		From:       monday.AddDate(0, 0, 2),
+		IsHoliday:  func(d ergani.Date) bool { return d.Equal(holiday.Time) },
+		Exceptions: []ergani.EmployeeDailySchedule{short},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(daily.EmployeeSchedules) != 12 || !daily.StartDate.Equal(monday.AddDate(0, 0, 2)) || !daily.EndDate.Equal(monday.AddDate(0, 0, 13)) {
+		t.Fatalf("Expected 12 days from Wednesday, got %d from %v", len(daily.EmployeeSchedules), daily.StartDate)
+	}
+	if es := daily.EmployeeSchedules[0]; len(es.WorkdayDetails) != 2 || es.WorkdayDetails[1].StartTime.Format("15:04") != "17:00" {
+		t.Errorf("Expected the split shift to be preserved, got %+v", es.WorkdayDetails)
+	}
+	if es := daily.EmployeeSchedules[2]; es.WorkdayDetails[0].WorkType != ergani.RestDay {
+		t.Errorf("Expected a rest day on the holiday, got %+v", es.WorkdayDetails)
+	}
+	if es := daily.EmployeeSchedules[8]; len(es.WorkdayDetails) != 1 || es.WorkdayDetails[0].EndTime.Format("15:04") != "13:00" {
+		t.Errorf("Expected the exception on Thursday, got %+v", es.WorkdayDetails)
+	}
+
+	back, exceptions, err := Consolidate(daily)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(back.EmployeeSchedules) != 7 || !back.StartDate.Equal(monday.AddDate(0, 0, 2)) {
+		t.Fatalf("Expected one weekly entry per weekday, got %d", len(back.EmployeeSchedules))
+	}
+	if es := back.EmployeeSchedules[0]; es.ScheduleDay.Weekday != time.Monday || len(es.WorkdayDetails) != 2 {
+		t.Errorf("Expected Monday's split shift first, got %+v", es)
+	}
+	// Friday and Thursday occur twice each. The regular Friday wins the tie over
+	// the holiday and the earlier Thursday over the short one.
+	if es := back.EmployeeSchedules[4]; es.ScheduleDay.Weekday != time.Friday || len(es.WorkdayDetails) != 2 {
+		t.Errorf("Expected Friday's split shift as the template, got %+v", es)
+	}
+	if len(exceptions.EmployeeSchedules) != 2 ||
+		!exceptions.EmployeeSchedules[0].ScheduleDate.Equal(holiday.Time) ||
+		!exceptions.EmployeeSchedules[1].ScheduleDate.Equal(short.ScheduleDate.Time) {
+		t.Errorf("Expected the holiday and the short day as exceptions, got %+v", exceptions.EmployeeSchedules)
+	}
+
+	if _, err := Expand(weekly, ExpandOptions{From: monday.AddDate(0, 1, 0)}); err == nil {
+		t.Error("Expected an error for a range outside the validity range")
+	}
+}
@@ -0,0 +1,51 @@
+package ergani_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+func TestScheduleIndex(t *testing.T) {
+	monday := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	work := func(from, to string) ergani.WorkdayDetails {
+		return testutil.Workday(t, ergani.WorkFromOffice, from, to)
+	}
+
+	index := ergani.NewScheduleIndex()
+	index.AddWeekly(ergani.CompanyWeeklySchedule{
+		StartDate: ergani.Date{Time: monday},
+		EndDate:   ergani.Date{Time: monday.AddDate(0, 0, 6)},
+		EmployeeSchedules: []ergani.EmployeeWeeklySchedule{
+			{EmployeeTaxID: "123456789", ScheduleDay: ergani.Weekday{Weekday: time.Monday}, WorkdayDetails: []ergani.WorkdayDetails{work("09:00", "13:00")}},
+			{EmployeeTaxID: "123456789", ScheduleDay: ergani.Weekday{Weekday: time.Monday}, WorkdayDetails: []ergani.WorkdayDetails{work("17:00", "21:00")}},
+			{EmployeeTaxID: "123456789", ScheduleDay: ergani.Weekday{Weekday: time.Tuesday}, WorkdayDetails: []ergani.WorkdayDetails{work("09:00", "17:00")}},
+		},
+	})
+	index.AddDaily(ergani.CompanyDailySchedule{
+		EmployeeSchedules: []ergani.EmployeeDailySchedule{
+			{EmployeeTaxID: "123456789", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 1)}, WorkdayDetails: []ergani.WorkdayDetails{{WorkType: ergani.RestDay}}},
+		},
+	})
+
+	day, ok := index.Lookup("123456789", monday.Add(10*time.Hour))
+	if !ok || day.Source != ergani.WeeklyScheduleDocument || len(day.WorkdayDetails) != 2 || !day.IsWork() {
+		t.Errorf("Expected both split periods of the weekly schedule, got %+v", day)
+	}
+
+	day, ok = index.Lookup("123456789", monday.AddDate(0, 0, 1))
+	if !ok || day.Source != ergani.DailyScheduleDocument || day.IsWork() || !day.Has(ergani.RestDay) {
+		t.Errorf("Expected the daily rest day to override the weekly schedule, got %+v", day)
+	}
+
+	if _, ok := index.Lookup("123456789", monday.AddDate(0, 0, 7)); ok {
+		t.Error("Expected no schedule outside the weekly validity range")
+	}
+
+	start, end := work("22:00", "06:00").On(monday)
+	if !start.Equal(monday.Add(22*time.Hour)) || !end.Equal(monday.Add(30*time.Hour)) {
+		t.Errorf("Expected a night period to end on the next day, got %v - %v", start, end)
+	}
+}
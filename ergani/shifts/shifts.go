@@ -0,0 +1,233 @@
+// Package shifts reconstructs worked shifts from work card movements.
+//
+// Every ergani.WorkCard is a single arrival or departure. Reconstruct pairs them per
+// employee into shifts, including shifts that cross midnight, reports the movements
+// that cannot be paired as findings and computes worked time per day and week.
+package shifts
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// DefaultMaxShift is the default longest time between an arrival and its departure.
+const DefaultMaxShift = 24 * time.Hour
+
+// FindingKind is the type of a pairing anomaly.
+type FindingKind string
+
+const (
+	// DoubleArrival is an arrival while the employee is already clocked in. The
+	// earlier arrival is kept.
+	DoubleArrival FindingKind = "DOUBLE_ARRIVAL"
+	// MissingDeparture is an arrival that is never followed by a departure within
+	// the maximum shift length.
+	MissingDeparture FindingKind = "MISSING_DEPARTURE"
+	// MissingArrival is a departure without a preceding arrival.
+	MissingArrival FindingKind = "MISSING_ARRIVAL"
+)
+
+// Finding is a work card movement that could not be paired into a shift.
+type Finding struct {
+	Kind     FindingKind
+	WorkCard ergani.WorkCard
+}
+
+// String implements the fmt.Stringer interface.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s %s at %s", f.Kind, f.WorkCard.EmployeeTaxID,
+		f.WorkCard.WorkCardMovementType, f.WorkCard.WorkCardMovementDateTime.Format(time.RFC3339))
+}
+
+// Shift is an arrival paired with its departure.
+type Shift struct {
+	EmployeeTaxID string
+	Arrival       ergani.WorkCard
+	Departure     ergani.WorkCard
+}
+
+// Start returns the arrival time.
+func (s Shift) Start() time.Time {
+	return s.Arrival.WorkCardMovementDateTime.Time
+}
+
+// End returns the departure time.
+func (s Shift) End() time.Time {
+	return s.Departure.WorkCardMovementDateTime.Time
+}
+
+// Duration returns the worked time of the shift.
+func (s Shift) Duration() time.Duration {
+	return s.End().Sub(s.Start())
+}
+
+// DayTotal is the time an employee worked on a calendar day.
+type DayTotal struct {
+	EmployeeTaxID string
+	Date          ergani.Date
+	Worked        time.Duration
+}
+
+// WeekTotal is the time an employee worked in an ISO week.
+type WeekTotal struct {
+	EmployeeTaxID string
+	Year          int
+	Week          int
+	Worked        time.Duration
+}
+
+// ByDay splits the shift at midnight in loc and returns the worked time per day.
+func (s Shift) ByDay(loc *time.Location) []DayTotal {
+	var totals []DayTotal
+	start, end := s.Start().In(loc), s.End().In(loc)
+	for start.Before(end) {
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		next := day.AddDate(0, 0, 1)
+		if next.After(end) {
+			next = end
+		}
+		totals = append(totals, DayTotal{EmployeeTaxID: s.EmployeeTaxID, Date: ergani.Date{Time: day}, Worked: next.Sub(start)})
+		start = next
+	}
+	return totals
+}
+
+// Options configures Reconstruct.
+type Options struct {
+	// MaxShift is the longest accepted shift. An arrival without a departure within
+	// MaxShift is reported as MissingDeparture. Defaults to DefaultMaxShift.
+	MaxShift time.Duration
+	// Now, if set, keeps arrivals less than MaxShift before it as open shifts
+	// instead of reporting them as MissingDeparture.
+	Now time.Time
+	// Location is used to split shifts into days. Defaults to time.Local.
+	Location *time.Location
+}
+
+// Result holds the reconstructed shifts and the anomalies found.
+type Result struct {
+	// Shifts are ordered by employee and arrival time.
+	Shifts   []Shift
+	Findings []Finding
+	// Open are arrivals of shifts that are still in progress at Options.Now.
+	Open []ergani.WorkCard
+
+	location *time.Location
+}
+
+// Reconstruct pairs work card movements into shifts. The movements may be in any
+// order and belong to several employees.
+func Reconstruct(cards []ergani.WorkCard, opts Options) Result {
+	if opts.MaxShift == 0 {
+		opts.MaxShift = DefaultMaxShift
+	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+
+	sorted := make([]ergani.WorkCard, len(cards))
+	copy(sorted, cards)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].EmployeeTaxID != sorted[j].EmployeeTaxID {
+			return sorted[i].EmployeeTaxID < sorted[j].EmployeeTaxID
+		}
+		return sorted[i].WorkCardMovementDateTime.Before(sorted[j].WorkCardMovementDateTime.Time)
+	})
+
+	result := Result{location: opts.Location}
+	var open *ergani.WorkCard
+	closeOpen := func() {
+		if open == nil {
+			return
+		}
+		if !opts.Now.IsZero() && opts.Now.Sub(open.WorkCardMovementDateTime.Time) < opts.MaxShift {
+			result.Open = append(result.Open, *open)
+		} else {
+			result.Findings = append(result.Findings, Finding{Kind: MissingDeparture, WorkCard: *open})
+		}
+		open = nil
+	}
+
+	for i := range sorted {
+		card := sorted[i]
+		if open != nil && (open.EmployeeTaxID != card.EmployeeTaxID ||
+			card.WorkCardMovementDateTime.Sub(open.WorkCardMovementDateTime.Time) > opts.MaxShift) {
+			closeOpen()
+		}
+
+		switch card.WorkCardMovementType {
+		case ergani.Arrival:
+			if open != nil {
+				result.Findings = append(result.Findings, Finding{Kind: DoubleArrival, WorkCard: card})
+				continue
+			}
+			open = &sorted[i]
+		case ergani.Departure:
+			if open == nil {
+				result.Findings = append(result.Findings, Finding{Kind: MissingArrival, WorkCard: card})
+				continue
+			}
+			result.Shifts = append(result.Shifts, Shift{EmployeeTaxID: card.EmployeeTaxID, Arrival: *open, Departure: card})
+			open = nil
+		}
+	}
+	closeOpen()
+
+	return result
+}
+
+// DailyTotals returns the worked time per employee and calendar day, splitting
+// shifts that cross midnight. Totals are ordered by employee and date.
+func (r Result) DailyTotals() []DayTotal {
+	type key struct {
+		taxID string
+		day   time.Time
+	}
+	loc := r.location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	worked := make(map[key]time.Duration)
+	var keys []key
+	for _, s := range r.Shifts {
+		for _, t := range s.ByDay(loc) {
+			k := key{t.EmployeeTaxID, t.Date.Time}
+			if _, ok := worked[k]; !ok {
+				keys = append(keys, k)
+			}
+			worked[k] += t.Worked
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].taxID != keys[j].taxID {
+			return keys[i].taxID < keys[j].taxID
+		}
+		return keys[i].day.Before(keys[j].day)
+	})
+	totals := make([]DayTotal, len(keys))
+	for i, k := range keys {
+		totals[i] = DayTotal{EmployeeTaxID: k.taxID, Date: ergani.Date{Time: k.day}, Worked: worked[k]}
+	}
+	return totals
+}
+
+// WeeklyTotals returns the worked time per employee and ISO week, ordered by
+// employee and week.
+func (r Result) WeeklyTotals() []WeekTotal {
+	var totals []WeekTotal
+	for _, day := range r.DailyTotals() {
+		year, week := day.Date.ISOWeek()
+		n := len(totals)
+		if n > 0 && totals[n-1].EmployeeTaxID == day.EmployeeTaxID && totals[n-1].Year == year && totals[n-1].Week == week {
+			totals[n-1].Worked += day.Worked
+			continue
+		}
+		totals = append(totals, WeekTotal{EmployeeTaxID: day.EmployeeTaxID, Year: year, Week: week, Worked: day.Worked})
+	}
+	return totals
+}
@@ -0,0 +1,90 @@
+package shifts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+func card(taxID string, movement ergani.WorkCardMovementType, at time.Time) ergani.WorkCard {
+	return ergani.WorkCard{
+		EmployeeTaxID:            taxID,
+		WorkCardMovementType:     movement,
+		WorkCardMovementDateTime: ergani.DateTime{Time: at},
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	day := func(d, h, m int) time.Time { return time.Date(2025, 7, d, h, m, 0, 0, time.UTC) }
+
+	cards := []ergani.WorkCard{
+		// Night shift crossing midnight, given out of order.
+		card("111111111", ergani.Departure, day(11, 6, 0)),
+		card("111111111", ergani.Arrival, day(10, 22, 0)),
+		// Double arrival followed by a departure.
+		card("222222222", ergani.Arrival, day(10, 9, 0)),
+		card("222222222", ergani.Arrival, day(10, 9, 2)),
+		card("222222222", ergani.Departure, day(10, 17, 0)),
+		// Departure without arrival, then an arrival that is never closed.
+		card("333333333", ergani.Departure, day(10, 17, 0)),
+		card("333333333", ergani.Arrival, day(11, 9, 0)),
+		// Shift still in progress.
+		card("444444444", ergani.Arrival, day(14, 9, 0)),
+	}
+
+	result := Reconstruct(cards, Options{Location: time.UTC, Now: day(14, 12, 0)})
+
+	if len(result.Shifts) != 2 {
+		t.Fatalf("Expected 2 shifts, got %+v", result.Shifts)
+	}
+	if d := result.Shifts[0].Duration(); d != 8*time.Hour {
+		t.Errorf("Expected the night shift to last 8h, got %v", d)
+	}
+	if d := result.Shifts[1].Duration(); d != 8*time.Hour {
+		t.Errorf("Expected the first arrival to be kept, got %v", d)
+	}
+
+	want := map[FindingKind]string{
+		DoubleArrival:    "222222222",
+		MissingArrival:   "333333333",
+		MissingDeparture: "333333333",
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("Expected %d findings, got %v", len(want), result.Findings)
+	}
+	for _, f := range result.Findings {
+		if want[f.Kind] != f.WorkCard.EmployeeTaxID {
+			t.Errorf("Unexpected finding %v", f)
+		}
+	}
+	if len(result.Open) != 1 || result.Open[0].EmployeeTaxID != "444444444" {
+		t.Errorf("Expected an open shift for 444444444, got %+v", result.Open)
+	}
+
+	daily := result.DailyTotals()
+	if len(daily) != 3 {
+		t.Fatalf("Expected 3 daily totals, got %+v", daily)
+	}
+	if daily[0].Worked != 2*time.Hour || daily[1].Worked != 6*time.Hour || daily[1].Date.Day() != 11 {
+		t.Errorf("Expected the night shift to be split at midnight, got %+v", daily[:2])
+	}
+
+	weekly := result.WeeklyTotals()
+	if len(weekly) != 2 || weekly[0].Worked != 8*time.Hour || weekly[0].Week != 28 {
+		t.Errorf("Unexpected weekly totals %+v", weekly)
+	}
+}
+
+func TestReconstructMaxShift(t *testing.T) {
+	start := time.Date(2025, 7, 10, 9, 0, 0, 0, time.UTC)
+	result := Reconstruct([]ergani.WorkCard{
+		card("111111111", ergani.Arrival, start),
+		card("111111111", ergani.Departure, start.Add(30*time.Hour)),
+	}, Options{MaxShift: 16 * time.Hour})
+
+	if len(result.Shifts) != 0 || len(result.Findings) != 2 ||
+		result.Findings[0].Kind != MissingDeparture || result.Findings[1].Kind != MissingArrival {
+		t.Errorf("Expected a too long shift to be reported as two findings, got %+v", result)
+	}
+}
@@ -0,0 +1,110 @@
+package ergani
+
+import (
+	"sort"
+	"time"
+)
+
+// NewAnnualStaffTable builds the annual staff table of a business branch from the
+// weekly schedule entries already declared for its employees. Entries are grouped
+// per employee in the order they first appear; the weekly hours, the contract type
+// and the breaks between split shifts are derived from the WorkdayDetails. Every
+// weekday is counted once: a later entry for the same employee and weekday, e.g.
+// from another week of a multi-week schedule, replaces the earlier one.
+// Fields that cannot be derived from a schedule (SSN, profession code and pay)
+// are left for the caller to fill in.
+func NewAnnualStaffTable(businessBranchNumber, year int, schedules []EmployeeWeeklySchedule) CompanyAnnualStaffTable {
+	table := CompanyAnnualStaffTable{
+		BusinessBranchNumber: businessBranchNumber,
+		Year:                 year,
+	}
+
+	index := make(map[string]int)
+	for _, es := range schedules {
+		i, ok := index[es.EmployeeTaxID]
+		if !ok {
+			i = len(table.Employees)
+			index[es.EmployeeTaxID] = i
+			table.Employees = append(table.Employees, StaffTableEmployee{
+				EmployeeTaxID:     es.EmployeeTaxID,
+				EmployeeLastName:  es.EmployeeLastName,
+				EmployeeFirstName: es.EmployeeFirstName,
+			})
+		}
+
+		day := StaffTableDay{
+			Day:            es.ScheduleDay,
+			WorkdayDetails: es.WorkdayDetails,
+			BreakMinutes:   breakMinutes(es.WorkdayDetails),
+		}
+		employee := &table.Employees[i]
+		replaced := false
+		for j := range employee.Days {
+			if employee.Days[j].Day.Weekday == es.ScheduleDay.Weekday {
+				employee.Days[j] = day
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			employee.Days = append(employee.Days, day)
+		}
+	}
+
+	for i := range table.Employees {
+		var weekly time.Duration
+		for _, day := range table.Employees[i].Days {
+			for _, wd := range day.WorkdayDetails {
+				if wd.WorkType.IsWork() {
+					weekly += wd.Duration()
+				}
+			}
+		}
+
+		hours := weekly.Hours()
+		table.Employees[i].WeeklyHours = hours
+		if hours >= FullTimeWeeklyHours-weeklyHoursTolerance {
+			table.Employees[i].ContractType = FullTime
+		} else {
+			table.Employees[i].ContractType = PartTime
+		}
+	}
+
+	return table
+}
+
+// Amending returns a copy of the table marked as a supplementary (amending) table
+// of the original submission.
+func (t CompanyAnnualStaffTable) Amending(original SubmissionResponse) CompanyAnnualStaffTable {
+	t.RelatedProtocolID = original.Protocol
+	t.RelatedProtocolDate = &Date{Time: original.SubmissionDate}
+	return t
+}
+
+// IsAmending reports whether the table is a supplementary (amending) table.
+func (t CompanyAnnualStaffTable) IsAmending() bool {
+	return t.RelatedProtocolID != ""
+}
+
+// breakMinutes returns the total gap between the working periods of a day.
+func breakMinutes(details []WorkdayDetails) int {
+	type period struct{ start, end int }
+
+	var periods []period
+	for _, wd := range details {
+		if !wd.WorkType.IsWork() {
+			continue
+		}
+		start := wd.StartTime.Hour()*60 + wd.StartTime.Minute()
+		periods = append(periods, period{start: start, end: start + int(wd.Duration().Minutes())})
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start < periods[j].start })
+
+	total := 0
+	for i := 1; i < len(periods); i++ {
+		if gap := periods[i].start - periods[i-1].end; gap > 0 {
+			total += gap
+		}
+	}
+	return total
+}
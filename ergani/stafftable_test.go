@@ -0,0 +1,96 @@
+package ergani
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewAnnualStaffTable(t *testing.T) {
+	at := func(h, m int) Time { return Time{Time: time.Date(0, 1, 1, h, m, 0, 0, time.UTC)} }
+
+	schedules := []EmployeeWeeklySchedule{
+		{
+			EmployeeTaxID:    "123456789",
+			EmployeeLastName: "Doe",
+			ScheduleDay:      Weekday{Weekday: time.Monday},
+			WorkdayDetails: []WorkdayDetails{
+				{WorkType: WorkFromOffice, StartTime: at(13, 0), EndTime: at(17, 0)},
+				{WorkType: WorkFromOffice, StartTime: at(9, 0), EndTime: at(12, 30)},
+			},
+		},
+		{
+			EmployeeTaxID: "987654321",
+			ScheduleDay:   Weekday{Weekday: time.Monday},
+			WorkdayDetails: []WorkdayDetails{
+				{WorkType: WorkFromHome, StartTime: at(9, 0), EndTime: at(13, 0)},
+			},
+		},
+		// The same weekday of the following week replaces the first one.
+		{
+			EmployeeTaxID: "987654321",
+			ScheduleDay:   Weekday{Weekday: time.Monday},
+			WorkdayDetails: []WorkdayDetails{
+				{WorkType: WorkFromHome, StartTime: at(9, 0), EndTime: at(14, 0)},
+			},
+		},
+		{
+			EmployeeTaxID: "123456789",
+			ScheduleDay:   Weekday{Weekday: time.Sunday},
+			WorkdayDetails: []WorkdayDetails{
+				{WorkType: RestDay, StartTime: at(0, 0), EndTime: at(0, 0)},
+			},
+		},
+	}
+
+	table := NewAnnualStaffTable(1, 2025, schedules)
+
+	if len(table.Employees) != 2 {
+		t.Fatalf("Expected 2 employees, got %d", len(table.Employees))
+	}
+
+	first := table.Employees[0]
+	if first.EmployeeTaxID != "123456789" || first.EmployeeLastName != "Doe" {
+		t.Errorf("Expected the first employee to be 123456789 Doe, got %s %s", first.EmployeeTaxID, first.EmployeeLastName)
+	}
+	if len(first.Days) != 2 {
+		t.Fatalf("Expected 2 days for the first employee, got %d", len(first.Days))
+	}
+	if first.Days[0].BreakMinutes != 30 {
+		t.Errorf("Expected a 30 minute break on Monday, got %d", first.Days[0].BreakMinutes)
+	}
+	if first.WeeklyHours != 7.5 {
+		t.Errorf("Expected 7.5 weekly hours, got %g", first.WeeklyHours)
+	}
+	if first.ContractType != PartTime {
+		t.Errorf("Expected contract type %s, got %s", PartTime, first.ContractType)
+	}
+
+	second := table.Employees[1]
+	if len(second.Days) != 1 || second.WeeklyHours != 5 {
+		t.Errorf("Expected a repeated Monday to be counted once, got %d days and %g weekly hours", len(second.Days), second.WeeklyHours)
+	}
+}
+
+func TestCompanyAnnualStaffTable_Amending(t *testing.T) {
+	original := SubmissionResponse{
+		ID:             "sub555",
+		Protocol:       "proto666",
+		SubmissionDate: time.Date(2025, 7, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	table := CompanyAnnualStaffTable{BusinessBranchNumber: 1, Year: 2025}
+	if table.IsAmending() {
+		t.Error("Expected a new table not to be amending")
+	}
+
+	amending := table.Amending(original)
+	if !amending.IsAmending() {
+		t.Error("Expected the table to be amending")
+	}
+	if amending.RelatedProtocolID != "proto666" {
+		t.Errorf("Expected related protocol 'proto666', got '%s'", amending.RelatedProtocolID)
+	}
+	if err := amending.validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
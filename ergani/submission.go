@@ -0,0 +1,97 @@
+package ergani
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SubmissionFilter narrows down the submissions returned by ListSubmissions.
+// Zero values are not sent to the API.
+type SubmissionFilter struct {
+	From         time.Time
+	To           time.Time
+	DocumentType DocumentType
+	// BusinessBranchNumber is a pointer because branch 0 is the headquarters.
+	BusinessBranchNumber *int
+}
+
+// values encodes the filter as query parameters.
+func (f SubmissionFilter) values() url.Values {
+	q := url.Values{}
+	if !f.From.IsZero() {
+		q.Set("fromDate", f.From.Format("02/01/2006"))
+	}
+	if !f.To.IsZero() {
+		q.Set("toDate", f.To.Format("02/01/2006"))
+	}
+	if f.DocumentType != "" {
+		q.Set("type", string(f.DocumentType))
+	}
+	if f.BusinessBranchNumber != nil {
+		q.Set("branch", strconv.Itoa(*f.BusinessBranchNumber))
+	}
+	return q
+}
+
+// CompanyWorkCard decodes the document of a work card submission.
+func (s Submission) CompanyWorkCard() (CompanyWorkCard, error) {
+	var doc CompanyWorkCard
+	err := s.decode(WorkCardDocument, &doc)
+	return doc, err
+}
+
+// CompanyWorkCardCorrection decodes the document of a work card correction submission.
+func (s Submission) CompanyWorkCardCorrection() (CompanyWorkCardCorrection, error) {
+	var doc CompanyWorkCardCorrection
+	err := s.decode(WorkCardCorrectionDocument, &doc)
+	return doc, err
+}
+
+// CompanyOvertime decodes the document of an overtime submission.
+func (s Submission) CompanyOvertime() (CompanyOvertime, error) {
+	var doc CompanyOvertime
+	err := s.decode(OvertimeDocument, &doc)
+	return doc, err
+}
+
+// CompanyDailySchedule decodes the document of a daily schedule submission.
+func (s Submission) CompanyDailySchedule() (CompanyDailySchedule, error) {
+	var doc CompanyDailySchedule
+	err := s.decode(DailyScheduleDocument, &doc)
+	return doc, err
+}
+
+// CompanyWeeklySchedule decodes the document of a weekly schedule submission.
+func (s Submission) CompanyWeeklySchedule() (CompanyWeeklySchedule, error) {
+	var doc CompanyWeeklySchedule
+	err := s.decode(WeeklyScheduleDocument, &doc)
+	return doc, err
+}
+
+// CompanyEmploymentChange decodes the document of an employment change submission.
+func (s Submission) CompanyEmploymentChange() (CompanyEmploymentChange, error) {
+	var doc CompanyEmploymentChange
+	err := s.decode(EmploymentChangeDocument, &doc)
+	return doc, err
+}
+
+// CompanyAnnualStaffTable decodes the document of an annual staff table submission.
+func (s Submission) CompanyAnnualStaffTable() (CompanyAnnualStaffTable, error) {
+	var doc CompanyAnnualStaffTable
+	err := s.decode(AnnualStaffTableDocument, &doc)
+	return doc, err
+}
+
+// decode unmarshals the raw document into v after checking its type.
+func (s Submission) decode(want DocumentType, v interface{}) error {
+	if s.DocumentType != want {
+		return fmt.Errorf("submission %s is a %s document, not %s", s.Protocol, s.DocumentType, want)
+	}
+	if err := json.Unmarshal(s.Document, v); err != nil {
+		return fmt.Errorf("failed to decode %s document: %w", want, err)
+	}
+	return nil
+}
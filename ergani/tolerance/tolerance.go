@@ -0,0 +1,247 @@
+// Package tolerance decides whether the difference between a declared work time
+// schedule and a work card movement is permitted.
+//
+// Rules hold the grace periods around the declared start and end of a working
+// period and how movement times are rounded. A Model selects the Rules of an
+// employer and evaluates work cards against the applicable WorkdayDetails. Every
+// evaluation ends in an Outcome: the deviation is permitted, the extra work has to
+// be declared as Overtime, or the schedule has to be amended.
+package tolerance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+)
+
+// Outcome is the result of evaluating a deviation.
+type Outcome int
+
+const (
+	// Permitted means the movement is within the grace periods.
+	Permitted Outcome = iota
+	// NeedsOvertime means the employee worked beyond the declared period, so the
+	// extra time has to be declared as Overtime.
+	NeedsOvertime
+	// NeedsAmendment means the movement does not fit the declared schedule, e.g. a
+	// late arrival, an early departure or work on a day without working periods,
+	// so the schedule has to be amended.
+	NeedsAmendment
+)
+
+// String implements the fmt.Stringer interface.
+func (o Outcome) String() string {
+	switch o {
+	case Permitted:
+		return "PERMITTED"
+	case NeedsOvertime:
+		return "NEEDS_OVERTIME"
+	case NeedsAmendment:
+		return "NEEDS_AMENDMENT"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// RoundingMode is the direction in which movement times are rounded.
+type RoundingMode int
+
+const (
+	RoundNearest RoundingMode = iota
+	RoundUp
+	RoundDown
+)
+
+// Rounding rounds movement times to a multiple of Interval since midnight. A zero
+// Interval leaves times unchanged.
+type Rounding struct {
+	Interval time.Duration
+	Mode     RoundingMode
+}
+
+// Round rounds t in its location.
+func (r Rounding) Round(t time.Time) time.Time {
+	if r.Interval <= 0 {
+		return t
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	elapsed := t.Sub(midnight)
+	remainder := elapsed % r.Interval
+	if remainder == 0 {
+		return t
+	}
+	rounded := elapsed - remainder
+	if r.Mode == RoundUp || (r.Mode == RoundNearest && 2*remainder >= r.Interval) {
+		rounded += r.Interval
+	}
+	return midnight.Add(rounded)
+}
+
+// Rules are the grace periods and rounding applied to the movements of an employer.
+type Rules struct {
+	// EarlyArrival is how long before the declared start an employee may arrive.
+	EarlyArrival time.Duration
+	// LateArrival is how long after the declared start an employee may arrive.
+	LateArrival time.Duration
+	// EarlyDeparture is how long before the declared end an employee may leave.
+	EarlyDeparture time.Duration
+	// LateDeparture is how long after the declared end an employee may leave.
+	LateDeparture time.Duration
+	// ArrivalRounding and DepartureRounding are applied to the movement times
+	// before they are compared with the schedule.
+	ArrivalRounding   Rounding
+	DepartureRounding Rounding
+}
+
+// DefaultRules allow 15 minutes on either side of the declared times without rounding.
+var DefaultRules = Rules{
+	EarlyArrival:   15 * time.Minute,
+	LateArrival:    15 * time.Minute,
+	EarlyDeparture: 15 * time.Minute,
+	LateDeparture:  15 * time.Minute,
+}
+
+// Check rounds the movement time at and compares it with the declared start of
+// the period for arrivals or the declared end for departures. It returns the
+// deviation of the rounded time from scheduled, negative when the movement is
+// early, and its outcome.
+func (r Rules) Check(movement ergani.WorkCardMovementType, at, scheduled time.Time) (time.Duration, Outcome) {
+	if movement == ergani.Departure {
+		deviation := r.DepartureRounding.Round(at).Sub(scheduled)
+		switch {
+		case deviation > r.LateDeparture:
+			return deviation, NeedsOvertime
+		case -deviation > r.EarlyDeparture:
+			return deviation, NeedsAmendment
+		}
+		return deviation, Permitted
+	}
+
+	deviation := r.ArrivalRounding.Round(at).Sub(scheduled)
+	switch {
+	case -deviation > r.EarlyArrival:
+		return deviation, NeedsOvertime
+	case deviation > r.LateArrival:
+		return deviation, NeedsAmendment
+	}
+	return deviation, Permitted
+}
+
+// Model holds the default rules and the overrides of individual employers.
+type Model struct {
+	Default Rules
+	// Employers overrides Default for the given employer tax IDs.
+	Employers map[string]Rules
+	// Location is used to place the declared times on the calendar. Defaults to
+	// time.Local.
+	Location *time.Location
+}
+
+// NewModel creates a Model with the given default rules.
+func NewModel(rules Rules) *Model {
+	return &Model{Default: rules, Employers: make(map[string]Rules)}
+}
+
+// Rules returns the rules that apply to an employer.
+func (m *Model) Rules(employerTaxID string) Rules {
+	if r, ok := m.Employers[employerTaxID]; ok {
+		return r
+	}
+	return m.Default
+}
+
+// Evaluation is the result of evaluating a work card.
+type Evaluation struct {
+	Card ergani.WorkCard
+	// Date is the schedule date of the matched period, which is the previous day
+	// for the departure from a night shift.
+	Date ergani.Date
+	// Scheduled is the declared start (arrivals) or end (departures) the card was
+	// compared with. It is zero if no working period was declared.
+	Scheduled time.Time
+	// Deviation is the rounded movement time minus Scheduled.
+	Deviation time.Duration
+	Outcome   Outcome
+}
+
+// Evaluate compares a work card of an employer with the WorkdayDetails declared
+// for the employee on date. The card is matched to the working period whose start
+// (arrivals) or end (departures) is closest to it. Cards on days without working
+// periods need an amendment.
+func (m *Model) Evaluate(employerTaxID string, card ergani.WorkCard, date ergani.Date, details []ergani.WorkdayDetails) Evaluation {
+	evaluation := m.evaluate(employerTaxID, card, m.periods(date, details))
+	if evaluation.Date.IsZero() {
+		evaluation.Date = date
+	}
+	return evaluation
+}
+
+// EvaluateIndexed evaluates a work card against the schedule that schedules holds
+// for its employee. The periods of the previous day are considered as well, so
+// that movements after midnight are matched to the night shift they belong to.
+func (m *Model) EvaluateIndexed(schedules *ergani.ScheduleIndex, employerTaxID string, card ergani.WorkCard) Evaluation {
+	at := card.WorkCardMovementDateTime.In(m.location())
+	date := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, m.location())
+
+	var periods []period
+	for _, d := range []time.Time{date.AddDate(0, 0, -1), date} {
+		if day, ok := schedules.Lookup(card.EmployeeTaxID, d); ok {
+			periods = append(periods, m.periods(ergani.Date{Time: d}, day.WorkdayDetails)...)
+		}
+	}
+	evaluation := m.evaluate(employerTaxID, card, periods)
+	if evaluation.Date.IsZero() {
+		evaluation.Date = ergani.Date{Time: date}
+	}
+	return evaluation
+}
+
+type period struct {
+	date       ergani.Date
+	start, end time.Time
+}
+
+func (m *Model) periods(date ergani.Date, details []ergani.WorkdayDetails) []period {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, m.location())
+	var periods []period
+	for _, wd := range details {
+		if wd.WorkType.IsWork() {
+			start, end := wd.On(day)
+			periods = append(periods, period{date: ergani.Date{Time: day}, start: start, end: end})
+		}
+	}
+	return periods
+}
+
+func (m *Model) evaluate(employerTaxID string, card ergani.WorkCard, periods []period) Evaluation {
+	evaluation := Evaluation{Card: card, Outcome: NeedsAmendment}
+	at := card.WorkCardMovementDateTime.In(m.location())
+
+	var best time.Duration
+	for _, p := range periods {
+		scheduled := p.start
+		if card.WorkCardMovementType == ergani.Departure {
+			scheduled = p.end
+		}
+		distance := at.Sub(scheduled)
+		if distance < 0 {
+			distance = -distance
+		}
+		if evaluation.Scheduled.IsZero() || distance < best {
+			best = distance
+			evaluation.Date, evaluation.Scheduled = p.date, scheduled
+		}
+	}
+	if !evaluation.Scheduled.IsZero() {
+		evaluation.Deviation, evaluation.Outcome = m.Rules(employerTaxID).Check(card.WorkCardMovementType, at, evaluation.Scheduled)
+	}
+	return evaluation
+}
+
+func (m *Model) location() *time.Location {
+	if m.Location == nil {
+		return time.Local
+	}
+	return m.Location
+}
@@ -0,0 +1,94 @@
+package tolerance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/takispanag/ergani-go-sdk/ergani"
+	"github.com/takispanag/ergani-go-sdk/ergani/internal/testutil"
+)
+
+func card(movement ergani.WorkCardMovementType, at time.Time) ergani.WorkCard {
+	return ergani.WorkCard{EmployeeTaxID: "111111111", WorkCardMovementType: movement, WorkCardMovementDateTime: ergani.DateTime{Time: at}}
+}
+
+func TestRounding(t *testing.T) {
+	at := time.Date(2025, 7, 7, 8, 53, 0, 0, time.UTC)
+	tests := []struct {
+		rounding Rounding
+		want     string
+	}{
+		{Rounding{}, "08:53"},
+		{Rounding{Interval: 15 * time.Minute}, "09:00"},
+		{Rounding{Interval: 10 * time.Minute}, "08:50"},
+		{Rounding{Interval: 15 * time.Minute, Mode: RoundDown}, "08:45"},
+		{Rounding{Interval: 5 * time.Minute, Mode: RoundUp}, "08:55"},
+	}
+	for _, tt := range tests {
+		if got := tt.rounding.Round(at).Format("15:04"); got != tt.want {
+			t.Errorf("Expected %+v to round to %s, got %s", tt.rounding, tt.want, got)
+		}
+	}
+}
+
+func TestModel(t *testing.T) {
+	monday := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	at := func(day, h, m int) time.Time {
+		return monday.AddDate(0, 0, day).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	details := []ergani.WorkdayDetails{
+		testutil.Workday(t, ergani.WorkFromOffice, "09:00", "13:00"),
+		testutil.Workday(t, ergani.WorkFromOffice, "17:00", "21:00"),
+	}
+
+	model := NewModel(DefaultRules)
+	model.Location = time.UTC
+	model.Employers["123456789"] = Rules{
+		LateDeparture:     10 * time.Minute,
+		DepartureRounding: Rounding{Interval: 15 * time.Minute, Mode: RoundDown},
+	}
+
+	tests := []struct {
+		name      string
+		employer  string
+		card      ergani.WorkCard
+		scheduled string
+		deviation time.Duration
+		outcome   Outcome
+	}{
+		{"WithinGrace", "", card(ergani.Arrival, at(0, 8, 50)), "09:00", -10 * time.Minute, Permitted},
+		{"EarlyArrival", "", card(ergani.Arrival, at(0, 8, 30)), "09:00", -30 * time.Minute, NeedsOvertime},
+		{"LateArrival", "", card(ergani.Arrival, at(0, 17, 20)), "17:00", 20 * time.Minute, NeedsAmendment},
+		{"EarlyDeparture", "", card(ergani.Departure, at(0, 12, 30)), "13:00", -30 * time.Minute, NeedsAmendment},
+		{"LateDeparture", "", card(ergani.Departure, at(0, 21, 40)), "21:00", 40 * time.Minute, NeedsOvertime},
+		{"RoundedDown", "123456789", card(ergani.Departure, at(0, 21, 14)), "21:00", 0, Permitted},
+		{"EmployerOverride", "123456789", card(ergani.Departure, at(0, 21, 20)), "21:00", 15 * time.Minute, NeedsOvertime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := model.Evaluate(tt.employer, tt.card, ergani.Date{Time: monday}, details)
+			if e.Scheduled.Format("15:04") != tt.scheduled || e.Deviation != tt.deviation || e.Outcome != tt.outcome {
+				t.Errorf("Expected %v from %s, got %v of %v from %s", tt.outcome, tt.scheduled, e.Outcome, e.Deviation, e.Scheduled.Format("15:04"))
+			}
+		})
+	}
+
+	t.Run("RestDay", func(t *testing.T) {
+		e := model.Evaluate("", card(ergani.Arrival, at(0, 9, 0)), ergani.Date{Time: monday}, []ergani.WorkdayDetails{{WorkType: ergani.RestDay}})
+		if e.Outcome != NeedsAmendment || !e.Scheduled.IsZero() || !e.Date.Equal(monday) {
+			t.Errorf("Expected an amendment for work on a rest day, got %+v", e)
+		}
+	})
+
+	t.Run("Indexed", func(t *testing.T) {
+		index := ergani.NewScheduleIndex()
+		index.AddDaily(ergani.CompanyDailySchedule{EmployeeSchedules: []ergani.EmployeeDailySchedule{
+			{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday}, WorkdayDetails: []ergani.WorkdayDetails{testutil.Workday(t, ergani.WorkFromOffice, "22:00", "06:00")}},
+			{EmployeeTaxID: "111111111", ScheduleDate: ergani.Date{Time: monday.AddDate(0, 0, 1)}, WorkdayDetails: []ergani.WorkdayDetails{{WorkType: ergani.RestDay}}},
+		}})
+		e := model.EvaluateIndexed(index, "", card(ergani.Departure, at(1, 6, 45)))
+		if e.Outcome != NeedsOvertime || e.Deviation != 45*time.Minute || !e.Date.Equal(monday) {
+			t.Errorf("Expected the departure to match Monday's night shift, got %+v", e)
+		}
+	})
+}
@@ -2,9 +2,24 @@ package ergani
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
+// DocumentType identifies the kind of document submitted to the API. Its value is
+// the name of the document endpoint.
+type DocumentType string
+
+const (
+	WorkCardDocument           DocumentType = "WRKCardSE"
+	WorkCardCorrectionDocument DocumentType = "WRKCardCorr"
+	OvertimeDocument           DocumentType = "OvTime"
+	DailyScheduleDocument      DocumentType = "WTODaily"
+	WeeklyScheduleDocument     DocumentType = "WTOWeek"
+	EmploymentChangeDocument   DocumentType = "E9"
+	AnnualStaffTableDocument   DocumentType = "E4"
+)
+
 // WorkCardMovementType defines the type of work card movement (arrival or departure).
 type WorkCardMovementType string
 
@@ -28,6 +43,31 @@ const (
 	ErganiSystemsUnavailable LateDeclarationJustificationType = "ERGANI_SYSTEMS_UNAVAILABLE"
 )
 
+// WorkCardCorrectionType defines whether a submitted work card entry is corrected or cancelled.
+type WorkCardCorrectionType string
+
+const (
+	// Correction signifies that the movement time of a submitted entry is corrected.
+	Correction WorkCardCorrectionType = "CORRECTION"
+	// Cancellation signifies that a submitted entry (e.g., a duplicate clock-in) is cancelled.
+	Cancellation WorkCardCorrectionType = "CANCELLATION"
+)
+
+// CorrectionJustificationType defines the official reason for correcting or
+// cancelling a submitted work card entry.
+type CorrectionJustificationType string
+
+const (
+	// WrongMovementTime signifies that the entry was recorded with a wrong timestamp.
+	WrongMovementTime CorrectionJustificationType = "WRONG_MOVEMENT_TIME"
+	// DuplicateMovement signifies that the entry was recorded more than once.
+	DuplicateMovement CorrectionJustificationType = "DUPLICATE_MOVEMENT"
+	// WrongMovementType signifies that an arrival was recorded as a departure or vice versa.
+	WrongMovementType CorrectionJustificationType = "WRONG_MOVEMENT_TYPE"
+	// WrongEmployee signifies that the entry was recorded for the wrong employee.
+	WrongEmployee CorrectionJustificationType = "WRONG_EMPLOYEE"
+)
+
 // OvertimeJustificationType defines the official reason for an employee working overtime.
 type OvertimeJustificationType string
 
@@ -53,6 +93,29 @@ const (
 	Absent         ScheduleWorkType = "ABSENT"
 )
 
+// EmploymentChangeType defines the kind of change declared for an employee's working terms.
+type EmploymentChangeType string
+
+const (
+	// FullTimeToPartTime signifies the conversion of a full-time contract to part-time.
+	FullTimeToPartTime EmploymentChangeType = "FULL_TIME_TO_PART_TIME"
+	// PartTimeToFullTime signifies the conversion of a part-time contract to full-time.
+	PartTimeToFullTime EmploymentChangeType = "PART_TIME_TO_FULL_TIME"
+	// WorkingHoursChange signifies a change of the agreed working hours.
+	WorkingHoursChange EmploymentChangeType = "WORKING_HOURS_CHANGE"
+	// SpecialtyChange signifies a change of the employee's specialty.
+	SpecialtyChange EmploymentChangeType = "SPECIALTY_CHANGE"
+)
+
+// ContractType defines the employment contract under which an employee works.
+type ContractType string
+
+const (
+	FullTime     ContractType = "FULL_TIME"
+	PartTime     ContractType = "PART_TIME"
+	RotatingWork ContractType = "ROTATING_WORK"
+)
+
 // Custom time/date types for correct JSON formatting as required by the Ergani API.
 
 // Time wraps time.Time to format as "15:04" (HH:MM) for JSON marshaling.
@@ -63,6 +126,11 @@ func (t Time) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.Format("15:04"))
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface for the Time type.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	return unmarshalTime(data, "15:04", &t.Time)
+}
+
 // Date wraps time.Time to format as "02/01/2006" (DD/MM/YYYY) for JSON marshaling.
 type Date struct{ time.Time }
 
@@ -71,6 +139,11 @@ func (d Date) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.Format("02/01/2006"))
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface for the Date type.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	return unmarshalTime(data, "02/01/2006", &d.Time)
+}
+
 // DateTime wraps time.Time to format as ISO 8601 for JSON marshaling.
 type DateTime struct{ time.Time }
 
@@ -79,6 +152,29 @@ func (d DateTime) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.Format("2006-01-02T15:04:05.999Z07:00"))
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface for the DateTime type.
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	return unmarshalTime(data, time.RFC3339Nano, &d.Time)
+}
+
+// unmarshalTime parses a JSON string with the given layout. A JSON null leaves the
+// time untouched.
+func unmarshalTime(data []byte, layout string, t *time.Time) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.Parse(layout, s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
 // Bool wraps bool to format as "0" (false) or "1" (true) for JSON marshaling.
 type Bool bool
 
@@ -90,6 +186,20 @@ func (b Bool) MarshalJSON() ([]byte, error) {
 	return json.Marshal("0")
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface for the Bool type.
+// It accepts the "0"/"1" strings sent by the API as well as JSON booleans.
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"1"`, "true":
+		*b = true
+	case `"0"`, "false", "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid Bool: %s", data)
+	}
+	return nil
+}
+
 // Weekday wraps time.Weekday for custom JSON marshaling.
 type Weekday struct{ time.Weekday }
 
@@ -99,3 +209,16 @@ type Weekday struct{ time.Weekday }
 func (w Weekday) MarshalJSON() ([]byte, error) {
 	return json.Marshal(int(w.Weekday))
 }
+
+// UnmarshalJSON implements the json.Unmarshaler interface for the Weekday type.
+func (w *Weekday) UnmarshalJSON(data []byte) error {
+	var day int
+	if err := json.Unmarshal(data, &day); err != nil {
+		return err
+	}
+	if day < int(time.Sunday) || day > int(time.Saturday) {
+		return fmt.Errorf("invalid Weekday: %d", day)
+	}
+	w.Weekday = time.Weekday(day)
+	return nil
+}
@@ -16,6 +16,16 @@ const (
 	EmployerSystemsUnavailableCode = "002"
 	ErganiSystemsUnavailableCode   = "003"
 
+	// Work card correction type codes
+	CorrectionCode   = "1"
+	CancellationCode = "2"
+
+	// Work card correction justification codes
+	WrongMovementTimeCode = "001"
+	DuplicateMovementCode = "002"
+	WrongMovementTypeCode = "003"
+	WrongEmployeeCode     = "004"
+
 	// Overtime justification codes
 	AccidentPreventionCode         = "001"
 	UrgentSeasonalTasksCode        = "002"
@@ -32,6 +42,17 @@ const (
 	WorkFromHomeCode   = "ΤΗΛ"
 	RestDayCode        = "ΑΝ"
 	AbsentCode         = "ΜΕ"
+
+	// Employment change type codes
+	FullTimeToPartTimeCode = "001"
+	PartTimeToFullTimeCode = "002"
+	WorkingHoursChangeCode = "003"
+	SpecialtyChangeCode    = "004"
+
+	// Contract type codes
+	FullTimeCode     = "1"
+	PartTimeCode     = "2"
+	RotatingWorkCode = "3"
 )
 
 // mapWorkCardMovementType converts a WorkCardMovementType to its string representation
@@ -62,6 +83,34 @@ func mapLateDeclarationJustification(j LateDeclarationJustificationType) (string
 	}
 }
 
+// mapWorkCardCorrectionType converts a WorkCardCorrectionType to its API string code.
+func mapWorkCardCorrectionType(t WorkCardCorrectionType) (string, error) {
+	switch t {
+	case Correction:
+		return CorrectionCode, nil
+	case Cancellation:
+		return CancellationCode, nil
+	default:
+		return "", fmt.Errorf("invalid WorkCardCorrectionType: %v", t)
+	}
+}
+
+// mapCorrectionJustification converts a CorrectionJustificationType to its API string code.
+func mapCorrectionJustification(j CorrectionJustificationType) (string, error) {
+	switch j {
+	case WrongMovementTime:
+		return WrongMovementTimeCode, nil
+	case DuplicateMovement:
+		return DuplicateMovementCode, nil
+	case WrongMovementType:
+		return WrongMovementTypeCode, nil
+	case WrongEmployee:
+		return WrongEmployeeCode, nil
+	default:
+		return "", fmt.Errorf("invalid CorrectionJustificationType: %v", j)
+	}
+}
+
 // mapOvertimeJustification converts an OvertimeJustificationType to its API string code.
 func mapOvertimeJustification(j OvertimeJustificationType) (string, error) {
 	switch j {
@@ -104,6 +153,122 @@ func mapScheduleWorkType(t ScheduleWorkType) (string, error) {
 	}
 }
 
+// mapEmploymentChangeType converts an EmploymentChangeType to its API string code.
+func mapEmploymentChangeType(t EmploymentChangeType) (string, error) {
+	switch t {
+	case FullTimeToPartTime:
+		return FullTimeToPartTimeCode, nil
+	case PartTimeToFullTime:
+		return PartTimeToFullTimeCode, nil
+	case WorkingHoursChange:
+		return WorkingHoursChangeCode, nil
+	case SpecialtyChange:
+		return SpecialtyChangeCode, nil
+	default:
+		return "", fmt.Errorf("invalid EmploymentChangeType: %v", t)
+	}
+}
+
+// mapContractType converts a ContractType to its API string code.
+func mapContractType(t ContractType) (string, error) {
+	switch t {
+	case FullTime:
+		return FullTimeCode, nil
+	case PartTime:
+		return PartTimeCode, nil
+	case RotatingWork:
+		return RotatingWorkCode, nil
+	default:
+		return "", fmt.Errorf("invalid ContractType: %v", t)
+	}
+}
+
+// parseWorkCardMovementType converts an API string code back to a WorkCardMovementType.
+func parseWorkCardMovementType(code string) (WorkCardMovementType, error) {
+	for _, t := range []WorkCardMovementType{Arrival, Departure} {
+		if c, _ := mapWorkCardMovementType(t); c == code {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid work card movement type code: %q", code)
+}
+
+// parseLateDeclarationJustification converts an API string code back to a
+// LateDeclarationJustificationType.
+func parseLateDeclarationJustification(code string) (LateDeclarationJustificationType, error) {
+	for _, j := range []LateDeclarationJustificationType{PowerOutage, EmployerSystemsUnavailable, ErganiSystemsUnavailable} {
+		if c, _ := mapLateDeclarationJustification(j); c == code {
+			return j, nil
+		}
+	}
+	return "", fmt.Errorf("invalid late declaration justification code: %q", code)
+}
+
+// parseOvertimeJustification converts an API string code back to an OvertimeJustificationType.
+func parseOvertimeJustification(code string) (OvertimeJustificationType, error) {
+	for _, j := range []OvertimeJustificationType{
+		AccidentPreventionOrDamageRestoration, UrgentSeasonalTasks, ExceptionalWorkload,
+		SupplementaryTasks, LostHoursSuddenCauses, LostHoursOfficialHolidays,
+		LostHoursWeatherConditions, EmergencyClosureDay, NonWorkdayTasks,
+	} {
+		if c, _ := mapOvertimeJustification(j); c == code {
+			return j, nil
+		}
+	}
+	return "", fmt.Errorf("invalid overtime justification code: %q", code)
+}
+
+// parseScheduleWorkType converts an API string code back to a ScheduleWorkType.
+func parseScheduleWorkType(code string) (ScheduleWorkType, error) {
+	for _, t := range []ScheduleWorkType{WorkFromOffice, WorkFromHome, RestDay, Absent} {
+		if c, _ := mapScheduleWorkType(t); c == code {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid schedule work type code: %q", code)
+}
+
+// parseEmploymentChangeType converts an API string code back to an EmploymentChangeType.
+func parseEmploymentChangeType(code string) (EmploymentChangeType, error) {
+	for _, t := range []EmploymentChangeType{FullTimeToPartTime, PartTimeToFullTime, WorkingHoursChange, SpecialtyChange} {
+		if c, _ := mapEmploymentChangeType(t); c == code {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid employment change type code: %q", code)
+}
+
+// parseContractType converts an API string code back to a ContractType.
+func parseContractType(code string) (ContractType, error) {
+	for _, t := range []ContractType{FullTime, PartTime, RotatingWork} {
+		if c, _ := mapContractType(t); c == code {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid contract type code: %q", code)
+}
+
+// parseWorkCardCorrectionType converts an API string code back to a WorkCardCorrectionType.
+func parseWorkCardCorrectionType(code string) (WorkCardCorrectionType, error) {
+	for _, t := range []WorkCardCorrectionType{Correction, Cancellation} {
+		if c, _ := mapWorkCardCorrectionType(t); c == code {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid work card correction type code: %q", code)
+}
+
+// parseCorrectionJustification converts an API string code back to a
+// CorrectionJustificationType.
+func parseCorrectionJustification(code string) (CorrectionJustificationType, error) {
+	for _, j := range []CorrectionJustificationType{WrongMovementTime, DuplicateMovement, WrongMovementType, WrongEmployee} {
+		if c, _ := mapCorrectionJustification(j); c == code {
+			return j, nil
+		}
+	}
+	return "", fmt.Errorf("invalid correction justification code: %q", code)
+}
+
 // MarshalJSON is a custom marshaller for the WorkCard struct.
 // It ensures that enum types like WorkCardMovementType are converted to their
 // correct API string representations before marshaling to JSON.
@@ -135,6 +300,40 @@ func (wc WorkCard) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// MarshalJSON is a custom marshaller for the WorkCardCorrection struct.
+// It converts the correction type, movement type and justification enums to their
+// API string codes.
+func (wc WorkCardCorrection) MarshalJSON() ([]byte, error) {
+	type Alias WorkCardCorrection
+
+	correctionType, err := mapWorkCardCorrectionType(wc.CorrectionType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal WorkCardCorrection: %w", err)
+	}
+
+	movementType, err := mapWorkCardMovementType(wc.WorkCardMovementType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal WorkCardCorrection: %w", err)
+	}
+
+	justification, err := mapCorrectionJustification(wc.Justification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal WorkCardCorrection: %w", err)
+	}
+
+	return json.Marshal(&struct {
+		CorrectionType       string `json:"f_correction_type"`
+		WorkCardMovementType string `json:"f_type"`
+		Justification        string `json:"f_aitiologia"`
+		*Alias
+	}{
+		CorrectionType:       correctionType,
+		WorkCardMovementType: movementType,
+		Justification:        justification,
+		Alias:                (*Alias)(&wc),
+	})
+}
+
 // MarshalJSON is a custom marshaller for the Overtime struct.
 // It converts the OvertimeJustification enum to its API string code.
 func (o Overtime) MarshalJSON() ([]byte, error) {
@@ -173,6 +372,217 @@ func (wd WorkdayDetails) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// MarshalJSON is a custom marshaller for the EmploymentChange struct.
+// It converts the ChangeType and ContractType enums to their API string codes.
+func (ec EmploymentChange) MarshalJSON() ([]byte, error) {
+	type Alias EmploymentChange
+
+	changeType, err := mapEmploymentChangeType(ec.ChangeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EmploymentChange: %w", err)
+	}
+
+	contractType, err := mapContractType(ec.ContractType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal EmploymentChange: %w", err)
+	}
+
+	return json.Marshal(&struct {
+		ChangeType   string `json:"f_metavoli"`
+		ContractType string `json:"f_sxesi"`
+		*Alias
+	}{
+		ChangeType:   changeType,
+		ContractType: contractType,
+		Alias:        (*Alias)(&ec),
+	})
+}
+
+// MarshalJSON is a custom marshaller for the StaffTableEmployee struct.
+// It converts the ContractType enum to its API string code.
+func (se StaffTableEmployee) MarshalJSON() ([]byte, error) {
+	type Alias StaffTableEmployee
+
+	contractType, err := mapContractType(se.ContractType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal StaffTableEmployee: %w", err)
+	}
+
+	return json.Marshal(&struct {
+		ContractType string `json:"f_sxesi"`
+		*Alias
+	}{
+		ContractType: contractType,
+		Alias:        (*Alias)(&se),
+	})
+}
+
+// UnmarshalJSON is a custom unmarshaller for the WorkCard struct.
+// It converts the API string codes back to their enum types.
+func (wc *WorkCard) UnmarshalJSON(data []byte) error {
+	type Alias WorkCard
+	aux := &struct {
+		WorkCardMovementType         string  `json:"f_type"`
+		LateDeclarationJustification *string `json:"f_aitiologia"`
+		*Alias
+	}{
+		Alias: (*Alias)(wc),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	movementType, err := parseWorkCardMovementType(aux.WorkCardMovementType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal WorkCard: %w", err)
+	}
+	wc.WorkCardMovementType = movementType
+
+	wc.LateDeclarationJustification = nil
+	if aux.LateDeclarationJustification != nil && *aux.LateDeclarationJustification != "" {
+		j, err := parseLateDeclarationJustification(*aux.LateDeclarationJustification)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal WorkCard: %w", err)
+		}
+		wc.LateDeclarationJustification = &j
+	}
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaller for the Overtime struct.
+// It converts the OvertimeJustification API code back to its enum type.
+func (o *Overtime) UnmarshalJSON(data []byte) error {
+	type Alias Overtime
+	aux := &struct {
+		OvertimeJustification string `json:"f_reason"`
+		*Alias
+	}{
+		Alias: (*Alias)(o),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	justification, err := parseOvertimeJustification(aux.OvertimeJustification)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal Overtime: %w", err)
+	}
+	o.OvertimeJustification = justification
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaller for the WorkdayDetails struct.
+// It converts the WorkType API code back to its enum type.
+func (wd *WorkdayDetails) UnmarshalJSON(data []byte) error {
+	type Alias WorkdayDetails
+	aux := &struct {
+		WorkType string `json:"f_type"`
+		*Alias
+	}{
+		Alias: (*Alias)(wd),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	workType, err := parseScheduleWorkType(aux.WorkType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal WorkdayDetails: %w", err)
+	}
+	wd.WorkType = workType
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaller for the EmploymentChange struct.
+// It converts the ChangeType and ContractType API codes back to their enum types.
+func (ec *EmploymentChange) UnmarshalJSON(data []byte) error {
+	type Alias EmploymentChange
+	aux := &struct {
+		ChangeType   string `json:"f_metavoli"`
+		ContractType string `json:"f_sxesi"`
+		*Alias
+	}{
+		Alias: (*Alias)(ec),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	changeType, err := parseEmploymentChangeType(aux.ChangeType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal EmploymentChange: %w", err)
+	}
+	contractType, err := parseContractType(aux.ContractType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal EmploymentChange: %w", err)
+	}
+	ec.ChangeType = changeType
+	ec.ContractType = contractType
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaller for the StaffTableEmployee struct.
+// It converts the ContractType API code back to its enum type.
+func (se *StaffTableEmployee) UnmarshalJSON(data []byte) error {
+	type Alias StaffTableEmployee
+	aux := &struct {
+		ContractType string `json:"f_sxesi"`
+		*Alias
+	}{
+		Alias: (*Alias)(se),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	contractType, err := parseContractType(aux.ContractType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal StaffTableEmployee: %w", err)
+	}
+	se.ContractType = contractType
+	return nil
+}
+
+// UnmarshalJSON is a custom unmarshaller for the WorkCardCorrection struct.
+// It converts the API codes back to their enum types.
+func (wc *WorkCardCorrection) UnmarshalJSON(data []byte) error {
+	type Alias WorkCardCorrection
+	aux := &struct {
+		CorrectionType       string `json:"f_correction_type"`
+		WorkCardMovementType string `json:"f_type"`
+		Justification        string `json:"f_aitiologia"`
+		*Alias
+	}{
+		Alias: (*Alias)(wc),
+	}
+
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	correctionType, err := parseWorkCardCorrectionType(aux.CorrectionType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal WorkCardCorrection: %w", err)
+	}
+	movementType, err := parseWorkCardMovementType(aux.WorkCardMovementType)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal WorkCardCorrection: %w", err)
+	}
+	justification, err := parseCorrectionJustification(aux.Justification)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal WorkCardCorrection: %w", err)
+	}
+	wc.CorrectionType = correctionType
+	wc.WorkCardMovementType = movementType
+	wc.Justification = justification
+	return nil
+}
+
 // parseSubmissionResponse decodes the JSON body of a successful submission response
 // from the API into a slice of SubmissionResponse structs.
 func parseSubmissionResponse(resp *http.Response) ([]SubmissionResponse, error) {
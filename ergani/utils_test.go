@@ -186,4 +186,36 @@ func TestEnumMappings(t *testing.T) {
 			})
 		}
 	})
+	t.Run("EmploymentChangeType", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			input    EmploymentChangeType
+			expected string
+			hasError bool
+		}{
+			{"FullTimeToPartTime", FullTimeToPartTime, "001", false},
+			{"PartTimeToFullTime", PartTimeToFullTime, "002", false},
+			{"WorkingHoursChange", WorkingHoursChange, "003", false},
+			{"SpecialtyChange", SpecialtyChange, "004", false},
+			{"Invalid", EmploymentChangeType("INVALID"), "", true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := mapEmploymentChangeType(tt.input)
+				if tt.hasError {
+					if err == nil {
+						t.Errorf("Expected error for input %v, got nil", tt.input)
+					}
+				} else {
+					if err != nil {
+						t.Errorf("Unexpected error for input %v: %v", tt.input, err)
+					}
+					if result != tt.expected {
+						t.Errorf("Expected %s, got %s", tt.expected, result)
+					}
+				}
+			})
+		}
+	})
 }
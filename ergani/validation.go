@@ -0,0 +1,157 @@
+package ergani
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// FullTimeWeeklyHours is the statutory weekly working time of a full-time
+	// employee. Sectors with a shorter full-time week configure their own threshold
+	// through Config.FullTimeWeeklyHours.
+	FullTimeWeeklyHours = 40.0
+
+	// weeklyHoursTolerance absorbs rounding when comparing fractional weekly hours.
+	weeklyHoursTolerance = 0.01
+)
+
+// Duration returns the length of the working period. An EndTime that is not after
+// the StartTime is treated as ending on the following day (e.g., a night shift).
+func (wd WorkdayDetails) Duration() time.Duration {
+	start := wd.StartTime.Hour()*60 + wd.StartTime.Minute()
+	end := wd.EndTime.Hour()*60 + wd.EndTime.Minute()
+	if end <= start {
+		end += 24 * 60
+	}
+	return time.Duration(end-start) * time.Minute
+}
+
+// IsWork reports whether the work type counts as working time.
+func (t ScheduleWorkType) IsWork() bool {
+	return t == WorkFromOffice || t == WorkFromHome
+}
+
+// validate checks that the declared weekly hours are consistent with the declared
+// contract type and that the change type agrees with the resulting contract. A
+// full-time contract requires at least fullTimeHours per week, a part-time or
+// rotating contract fewer.
+func (ec EmploymentChange) validate(fullTimeHours float64) error {
+	if ec.WeeklyHours <= 0 {
+		return &ValidationError{Field: "WeeklyHours", Message: "weekly hours must be positive"}
+	}
+
+	switch ec.ContractType {
+	case FullTime:
+		if ec.WeeklyHours < fullTimeHours-weeklyHoursTolerance {
+			return &ValidationError{
+				Field:   "WeeklyHours",
+				Message: fmt.Sprintf("a full-time contract requires at least %g weekly hours, got %g", fullTimeHours, ec.WeeklyHours),
+			}
+		}
+	case PartTime, RotatingWork:
+		if ec.WeeklyHours >= fullTimeHours-weeklyHoursTolerance {
+			return &ValidationError{
+				Field:   "WeeklyHours",
+				Message: fmt.Sprintf("a %s contract requires fewer than %g weekly hours, got %g", ec.ContractType, fullTimeHours, ec.WeeklyHours),
+			}
+		}
+	default:
+		return &ValidationError{Field: "ContractType", Message: fmt.Sprintf("invalid ContractType: %v", ec.ContractType)}
+	}
+
+	if ec.ChangeType == FullTimeToPartTime && ec.ContractType != PartTime {
+		return &ValidationError{Field: "ContractType", Message: "a conversion to part-time must declare a PART_TIME contract"}
+	}
+	if ec.ChangeType == PartTimeToFullTime && ec.ContractType != FullTime {
+		return &ValidationError{Field: "ContractType", Message: "a conversion to full-time must declare a FULL_TIME contract"}
+	}
+
+	return nil
+}
+
+// CheckWeeklySchedule cross-checks a weekly schedule against the change. Schedules
+// that end before the change takes effect are not affected. Otherwise the working
+// hours declared for the employee must not exceed the hours of the new contract.
+func (ec EmploymentChange) CheckWeeklySchedule(schedule CompanyWeeklySchedule) error {
+	if schedule.EndDate.Before(ec.ChangeDate.Time) {
+		return nil
+	}
+
+	var scheduled time.Duration
+	for _, es := range schedule.EmployeeSchedules {
+		if es.EmployeeTaxID != ec.EmployeeTaxID {
+			continue
+		}
+		for _, wd := range es.WorkdayDetails {
+			if wd.WorkType.IsWork() {
+				scheduled += wd.Duration()
+			}
+		}
+	}
+
+	if hours := scheduled.Hours(); hours > ec.WeeklyHours+weeklyHoursTolerance {
+		return &ValidationError{
+			Field: "WeeklyHours",
+			Message: fmt.Sprintf("weekly schedule declares %g hours for employee %s, exceeding the %g hours of the %s contract",
+				hours, ec.EmployeeTaxID, ec.WeeklyHours, ec.ContractType),
+		}
+	}
+	return nil
+}
+
+// validate checks that a supplementary (amending) table references its original
+// submission with both a protocol number and a protocol date.
+func (t CompanyAnnualStaffTable) validate() error {
+	if t.RelatedProtocolID != "" && t.RelatedProtocolDate == nil {
+		return &ValidationError{Field: "RelatedProtocolDate", Message: "an amending staff table requires the related protocol date"}
+	}
+	if t.RelatedProtocolID == "" && t.RelatedProtocolDate != nil {
+		return &ValidationError{Field: "RelatedProtocolID", Message: "an amending staff table requires the related protocol number"}
+	}
+	return nil
+}
+
+// validate checks that the corrections reference an original submission and that
+// every entry is still within the allowed correction window at the given time.
+func (cc CompanyWorkCardCorrection) validate(now time.Time, window time.Duration) error {
+	if cc.RelatedProtocolID == "" {
+		return &ValidationError{Field: "RelatedProtocolID", Message: "a work card correction must reference the original protocol"}
+	}
+	if len(cc.Corrections) == 0 {
+		return &ValidationError{Field: "Corrections", Message: "at least one correction is required"}
+	}
+
+	for _, wc := range cc.Corrections {
+		original := wc.OriginalMovementDateTime.Time
+		if now.Sub(original) > window {
+			return &ValidationError{
+				Field:   "OriginalMovementDateTime",
+				Message: fmt.Sprintf("movement of %s at %s is outside the %v correction window", wc.EmployeeTaxID, original.Format(time.RFC3339), window),
+			}
+		}
+
+		switch wc.CorrectionType {
+		case Correction:
+			if wc.CorrectedMovementDateTime == nil {
+				return &ValidationError{Field: "CorrectedMovementDateTime", Message: "a correction requires the corrected movement time"}
+			}
+			corrected := wc.CorrectedMovementDateTime.Time
+			if corrected.After(now) {
+				return &ValidationError{Field: "CorrectedMovementDateTime", Message: "the corrected movement time cannot be in the future"}
+			}
+			if diff := corrected.Sub(original); diff > window || diff < -window {
+				return &ValidationError{
+					Field:   "CorrectedMovementDateTime",
+					Message: fmt.Sprintf("the corrected movement time is more than %v away from the original", window),
+				}
+			}
+		case Cancellation:
+			if wc.CorrectedMovementDateTime != nil {
+				return &ValidationError{Field: "CorrectedMovementDateTime", Message: "a cancellation cannot carry a corrected movement time"}
+			}
+		default:
+			return &ValidationError{Field: "CorrectionType", Message: fmt.Sprintf("invalid WorkCardCorrectionType: %v", wc.CorrectionType)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package ergani
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkdayDetails_Duration(t *testing.T) {
+	day := WorkdayDetails{
+		StartTime: Time{Time: time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)},
+		EndTime:   Time{Time: time.Date(0, 1, 1, 17, 30, 0, 0, time.UTC)},
+	}
+	if got := day.Duration(); got != 8*time.Hour+30*time.Minute {
+		t.Errorf("Expected 8h30m, got %v", got)
+	}
+
+	night := WorkdayDetails{
+		StartTime: Time{Time: time.Date(0, 1, 1, 22, 0, 0, 0, time.UTC)},
+		EndTime:   Time{Time: time.Date(0, 1, 1, 6, 0, 0, 0, time.UTC)},
+	}
+	if got := night.Duration(); got != 8*time.Hour {
+		t.Errorf("Expected cross-midnight shift to last 8h, got %v", got)
+	}
+}
+
+func TestEmploymentChange_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        EmploymentChange
+		fullTimeHours float64
+		hasError      bool
+	}{
+		{"FullTime40", EmploymentChange{ContractType: FullTime, WeeklyHours: 40}, FullTimeWeeklyHours, false},
+		{"FullTime30", EmploymentChange{ContractType: FullTime, WeeklyHours: 30}, FullTimeWeeklyHours, true},
+		{"FullTime37.5", EmploymentChange{ContractType: FullTime, WeeklyHours: 37.5}, 37.5, false},
+		{"PartTime20", EmploymentChange{ContractType: PartTime, WeeklyHours: 20}, FullTimeWeeklyHours, false},
+		{"PartTime40", EmploymentChange{ContractType: PartTime, WeeklyHours: 40}, FullTimeWeeklyHours, true},
+		{"PartTime37.5", EmploymentChange{ContractType: PartTime, WeeklyHours: 37.5}, 37.5, true},
+		{"ZeroHours", EmploymentChange{ContractType: PartTime}, FullTimeWeeklyHours, true},
+		{"ConversionMismatch", EmploymentChange{ChangeType: FullTimeToPartTime, ContractType: FullTime, WeeklyHours: 40}, FullTimeWeeklyHours, true},
+		{"InvalidContract", EmploymentChange{ContractType: ContractType("INVALID"), WeeklyHours: 20}, FullTimeWeeklyHours, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change.validate(tt.fullTimeHours)
+			if tt.hasError && err == nil {
+				t.Error("Expected validation error, got nil")
+			}
+			if !tt.hasError && err != nil {
+				t.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEmploymentChange_CheckWeeklySchedule(t *testing.T) {
+	change := EmploymentChange{
+		EmployeeTaxID: "123456789",
+		ChangeType:    FullTimeToPartTime,
+		ChangeDate:    Date{Time: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC)},
+		ContractType:  PartTime,
+		WeeklyHours:   20,
+	}
+
+	fiveDays := func(from, to int) []EmployeeWeeklySchedule {
+		var rows []EmployeeWeeklySchedule
+		for d := time.Monday; d <= time.Friday; d++ {
+			rows = append(rows, EmployeeWeeklySchedule{
+				EmployeeTaxID: "123456789",
+				ScheduleDay:   Weekday{Weekday: d},
+				WorkdayDetails: []WorkdayDetails{{
+					WorkType:  WorkFromOffice,
+					StartTime: Time{Time: time.Date(0, 1, 1, from, 0, 0, 0, time.UTC)},
+					EndTime:   Time{Time: time.Date(0, 1, 1, to, 0, 0, 0, time.UTC)},
+				}},
+			})
+		}
+		return rows
+	}
+
+	schedule := CompanyWeeklySchedule{
+		StartDate:         Date{Time: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC)},
+		EndDate:           Date{Time: time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC)},
+		EmployeeSchedules: fiveDays(9, 17),
+	}
+	if err := change.CheckWeeklySchedule(schedule); err == nil {
+		t.Error("Expected a 40 hour schedule to conflict with a 20 hour contract")
+	}
+
+	schedule.EmployeeSchedules = fiveDays(9, 13)
+	if err := change.CheckWeeklySchedule(schedule); err != nil {
+		t.Errorf("Unexpected error for a 20 hour schedule: %v", err)
+	}
+
+	past := CompanyWeeklySchedule{
+		StartDate:         Date{Time: time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)},
+		EndDate:           Date{Time: time.Date(2025, 7, 13, 0, 0, 0, 0, time.UTC)},
+		EmployeeSchedules: fiveDays(9, 17),
+	}
+	if err := change.CheckWeeklySchedule(past); err != nil {
+		t.Errorf("Expected schedules ending before the change to be unaffected, got: %v", err)
+	}
+}